@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/Percona-Lab/percona-version-service/server"
+)
+
+type flags struct {
+	Dir string `default:"sources" help:"Directory where operator and dependency source files are stored"`
+}
+
+func main() {
+	var opts flags
+	kong.Parse(
+		&opts,
+		kong.Name("check-dep-rules"),
+		kong.Description("Reports dependency rules whose results change with semver operators or differ between jsonlogic v2 and v3."),
+		kong.UsageOnError(),
+		kong.ConfigureHelp(kong.HelpOptions{
+			Compact: true,
+		}),
+	)
+
+	report, err := server.CheckDepRules([]server.Layer{{Name: opts.Dir, Sources: server.NewDirSourceStore(opts.Dir)}})
+	if err != nil {
+		log.Fatalf("failed to check dependency rules: %v", err)
+	}
+	for _, i := range report.Issues {
+		fmt.Println(i)
+	}
+	if len(report.Issues) > 0 {
+		os.Exit(1)
+	}
+}
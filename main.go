@@ -11,6 +11,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -71,22 +72,62 @@ func main() {
 		}
 	}
 
+	var reloadInterval time.Duration
+	if v := os.Getenv("SOURCES_RELOAD_INTERVAL"); v != "" {
+		reloadInterval, err = time.ParseDuration(v)
+		if err != nil {
+			logger.Fatal("invalid SOURCES_RELOAD_INTERVAL", zap.Error(err))
+		}
+	}
+
 	s := grpc.NewServer(grpcServerLogOpt(logger))
-	metadataSub, err := fs.Sub(metaSources, "sources/metadata")
-	if err != nil {
-		logger.Fatal("could not create sub directory for sources/metadata", zap.Error(err))
+	var metadataSub, releaseNotesSub fs.FS
+	if reloadInterval > 0 {
+		// Embedded files never change, so serve them from disk to be able to reload them.
+		logger.Info("serving metadata and release notes from disk", zap.String("dir", "sources"))
+		metadataSub = os.DirFS("sources/metadata")
+		releaseNotesSub = os.DirFS("sources/release-notes")
+	} else {
+		metadataSub, err = fs.Sub(metaSources, "sources/metadata")
+		if err != nil {
+			logger.Fatal("could not create sub directory for sources/metadata", zap.Error(err))
+		}
+		releaseNotesSub, err = fs.Sub(releaseNoteSources, "sources/release-notes")
+		if err != nil {
+			logger.Fatal("could not create sub directory for sources/release-notes", zap.Error(err))
+		}
 	}
-	releaseNotesSub, err := fs.Sub(releaseNoteSources, "sources/release-notes")
-	if err != nil {
-		logger.Fatal("could not create sub directory for sources/release-notes", zap.Error(err))
+
+	layers := []server.Layer{{
+		Sources:      server.NewDirSourceStore("./sources"),
+		Metadata:     metadataSub,
+		ReleaseNotes: releaseNotesSub,
+	}}
+	layers = append(layers, overlayLayers(os.Getenv("SOURCES_OVERLAYS"))...)
+
+	var opts []server.Option
+	if file := os.Getenv("REGISTRY_REWRITES_FILE"); file != "" {
+		registries, err := server.ReadRegistryRewrites(file)
+		if err != nil {
+			logger.Fatal("could not read registry rewrites", zap.Error(err))
+		}
+		opts = append(opts, server.WithRegistryRewrites(registries))
+	}
+	if token := os.Getenv("EMBARGO_OVERRIDE_TOKEN"); token != "" {
+		opts = append(opts, server.WithEmbargoOverrideToken(token))
 	}
 
-	backend, err := server.New(metadataSub, releaseNotesSub)
+	backend, err := server.NewLayered(layers, opts...)
 	if err != nil {
 		logger.Fatal("could not create backend", zap.Error(err))
 	}
 	pbVersion.RegisterVersionServiceServer(s, backend)
 
+	if reloadInterval > 0 {
+		logger.Info("watching sources for changes", zap.Duration("interval", reloadInterval))
+		go backend.Watch(context.Background(), reloadInterval, logger)
+	}
+
 	logger.Info("serving gRPC", zap.String("Addr", "http://"+addr))
 
 	go func() {
@@ -109,7 +150,7 @@ func main() {
 		logger.Fatal("failed to dial server", zap.Error(err), zap.String("dialAddr", dialAddr))
 	}
 
-	gwmux := runtime.NewServeMux()
+	gwmux := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(headerMatcher))
 
 	err = pbVersion.RegisterVersionServiceHandler(context.Background(), gwmux, conn)
 	if err != nil {
@@ -148,6 +189,38 @@ func main() {
 	}
 }
 
+// headerMatcher forwards the registry mirror, explain and embargo override headers to gRPC metadata
+// in addition to the default ones.
+func headerMatcher(key string) (string, bool) {
+	for _, h := range []string{server.RegistryHeader, server.ExplainHeader, server.EmbargoOverrideHeader} {
+		if strings.EqualFold(key, h) {
+			return h, true
+		}
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// overlayLayers returns source layers for a comma-separated list of directories.
+// Each directory has the same layout as the sources directory.
+func overlayLayers(dirs string) []server.Layer {
+	var layers []server.Layer
+	for _, dir := range strings.Split(dirs, ",") {
+		dir = strings.TrimSpace(dir)
+		if dir == "" {
+			continue
+		}
+
+		layers = append(layers, server.Layer{
+			Name:         dir,
+			Sources:      server.NewDirSourceStore(dir),
+			Metadata:     os.DirFS(filepath.Join(dir, "metadata")),
+			ReleaseNotes: os.DirFS(filepath.Join(dir, "release-notes")),
+		})
+	}
+
+	return layers
+}
+
 func initLogger() *zap.Logger {
 	logConf := zap.NewProductionEncoderConfig()
 	logConf.EncodeTime = func(time time.Time, encoder zapcore.PrimitiveArrayEncoder) {
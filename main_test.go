@@ -11,7 +11,9 @@ import (
 func TestBackend_create(t *testing.T) {
 	sub, err := fs.Sub(metaSources, "sources/metadata")
 	require.NoError(t, err)
+	releaseNotesSub, err := fs.Sub(releaseNoteSources, "sources/release-notes")
+	require.NoError(t, err)
 
-	_, err = server.New(sub)
+	_, err = server.New(server.NewDirSourceStore("./sources"), sub, releaseNotesSub)
 	require.NoError(t, err)
 }
@@ -0,0 +1,194 @@
+package server
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+)
+
+const (
+	archAMD64 = "amd64"
+	archARM64 = "arm64"
+	archMulti = "multi"
+)
+
+// compatibility is the Kubernetes version, platform and CPU architecture of a cluster.
+// A nil compatibility supports everything.
+type compatibility struct {
+	kube     *semver.Version
+	platform string
+	arch     string
+}
+
+// newCompatibility parses the Kubernetes version, platform and architecture of a request.
+// It returns nil if none of them is set.
+func newCompatibility(kubeVersion, platform, arch string) (*compatibility, error) {
+	if kubeVersion == "" && platform == "" && arch == "" {
+		return nil, nil
+	}
+
+	c := &compatibility{platform: strings.ToLower(platform), arch: strings.ToLower(arch)}
+	switch c.arch {
+	case "", archAMD64, archARM64, archMulti:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid arch: %s", arch)
+	}
+	if kubeVersion != "" {
+		v, err := semver.NewVersion(kubeVersion)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid kube version: %s", kubeVersion)
+		}
+		// providers add suffixes to Kubernetes versions, e.g. v1.30.4-eks-a737599,
+		// which semver constraints would treat as prereleases
+		c.kube = releaseVersion(v)
+	}
+	return c, nil
+}
+
+func (c *compatibility) String() string {
+	var s []string
+	if c.kube != nil {
+		s = append(s, "Kubernetes "+c.kube.String())
+	}
+	if c.platform != "" {
+		s = append(s, "platform "+c.platform)
+	}
+	if c.arch != "" {
+		s = append(s, "arch "+c.arch)
+	}
+	return strings.Join(s, ", ")
+}
+
+// supports reports whether the cluster matches the supported Kubernetes versions constraint and platforms.
+// Invalid constraints are rejected by source validation and do not restrict anything.
+func (c *compatibility) supports(kubeVersions string, platforms []string) bool {
+	return c.unsupported(kubeVersions, platforms) == ""
+}
+
+// unsupported returns why the cluster does not match the supported Kubernetes versions constraint
+// and platforms or an empty string if it does.
+func (c *compatibility) unsupported(kubeVersions string, platforms []string) string {
+	if c == nil {
+		return ""
+	}
+	if c.kube != nil && kubeVersions != "" {
+		if constraint, err := semver.NewConstraint(kubeVersions); err == nil && !constraint.Check(c.kube) {
+			return fmt.Sprintf("Kubernetes %s does not match %s", c.kube, kubeVersions)
+		}
+	}
+	if c.platform != "" && len(platforms) > 0 &&
+		!slices.ContainsFunc(platforms, func(p string) bool { return strings.EqualFold(p, c.platform) }) {
+		return fmt.Sprintf("platform %s is not one of %s", c.platform, strings.Join(platforms, ", "))
+	}
+	return ""
+}
+
+// unsupportedVersion returns why the cluster cannot run the image of v or an empty string if it can:
+// v must support the cluster and have a digest for its architecture, both digests for multi.
+func (c *compatibility) unsupportedVersion(v *pbVersion.Version) string {
+	if c == nil {
+		return ""
+	}
+
+	switch c.arch {
+	case archAMD64:
+		if v.ImageHash == "" {
+			return "no amd64 image digest"
+		}
+	case archARM64:
+		if v.ImageHashArm64 == "" {
+			return "no arm64 image digest"
+		}
+	case archMulti:
+		if v.ImageHash == "" || v.ImageHashArm64 == "" {
+			return "no amd64 and arm64 image digests"
+		}
+	}
+	return c.unsupported(v.SupportedKubeVersions, v.SupportedPlatforms)
+}
+
+// selectDigest makes image_hash of v the digest for the architecture of the cluster.
+func (c *compatibility) selectDigest(v *pbVersion.Version) {
+	switch c.arch {
+	case archAMD64:
+		v.ImageHashArm64 = ""
+	case archARM64:
+		v.ImageHash = v.ImageHashArm64
+	}
+}
+
+// filter drops operator and component versions not supporting the cluster from versions
+// and selects the digests for its architecture. It fails with FailedPrecondition if no operator
+// version or no version of a required component, e.g. the database, is left. Other components
+// left without versions get a warning in vs.
+func (c *compatibility) filter(vs *pbVersion.VersionResponse, e *explanation, required ...string) error {
+	if c == nil {
+		return nil
+	}
+
+	var operators []*pbVersion.OperatorVersion
+	for _, ov := range vs.Versions {
+		if c.supports(ov.SupportedKubeVersions, ov.SupportedPlatforms) {
+			operators = append(operators, ov)
+		}
+	}
+	if len(operators) == 0 && len(vs.Versions) > 0 {
+		return status.Errorf(codes.FailedPrecondition, "operator %s does not support %s", vs.Versions[0].Operator, c)
+	}
+	vs.Versions = operators
+
+	for _, ov := range vs.Versions {
+		if ov.Matrix == nil {
+			continue
+		}
+		var components []string
+		rangeMatrix(ov.Matrix, func(component string, versions map[string]*pbVersion.Version) {
+			if len(versions) > 0 {
+				components = append(components, component)
+			}
+		})
+
+		var incompatible, emptied []string
+		for _, component := range components {
+			t := e.component(component)
+			_ = filterComponent(ov.Matrix, component, func(versions map[string]*pbVersion.Version) error {
+				maps.DeleteFunc(versions, func(k string, v *pbVersion.Version) bool {
+					reason := c.unsupportedVersion(v)
+					if reason != "" {
+						t.drop(k, "%s", reason)
+					}
+					return reason != ""
+				})
+				switch {
+				case len(versions) > 0:
+				case slices.Contains(required, component):
+					incompatible = append(incompatible, component)
+				default:
+					emptied = append(emptied, component)
+				}
+				for _, v := range versions {
+					c.selectDigest(v)
+				}
+				return nil
+			})
+		}
+		if len(incompatible) > 0 {
+			slices.Sort(incompatible)
+			return status.Errorf(codes.FailedPrecondition, "no %s version of operator %s supports %s",
+				strings.Join(incompatible, ", "), ov.Operator, c)
+		}
+		slices.Sort(emptied)
+		for _, component := range emptied {
+			vs.Warnings = append(vs.Warnings, fmt.Sprintf("no %s version of operator %s supports %s", component, ov.Operator, c))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,269 @@
+package server
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+)
+
+func TestBackend_compatibility(t *testing.T) {
+	t.Parallel()
+
+	overlay := Layer{Sources: MemorySourceStore{
+		"operator.1.0.0.psmdb-operator.json": []byte(`{
+  "versions": [
+    {
+      "supported_kube_versions": ">= 1.28, < 1.34",
+      "supported_platforms": ["kubernetes", "openshift", "eks", "gke"],
+      "matrix": {
+        "mongod": {
+          "7.0.14-8": {"supported_kube_versions": "< 1.33"},
+          "7.0.15-9": {"supported_kube_versions": "< 1.33"},
+          "8.0.4-1": {"supported_kube_versions": ">= 1.30", "supported_platforms": ["kubernetes", "eks", "gke"]}
+        },
+        "backup": {
+          "2.7.0": {"supported_kube_versions": "< 1.33"},
+          "2.8.0": {"supported_kube_versions": "< 1.33"}
+        }
+      }
+    }
+  ]
+}`),
+	}}
+	b, err := NewLayered([]Layer{{Sources: testSources}, overlay})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		kubeVersion   string
+		platform      string
+		backupVersion string
+		backups       bool
+		wantLatest    string
+		wantMongod    []string
+		wantBackup    []string
+		wantWarnings  []string
+		code          codes.Code
+		operatorCode  codes.Code
+	}{
+		"not set": {
+			wantLatest: "8.0.4-1",
+			wantMongod: []string{"7.0.14-8", "7.0.15-9", "8.0.4-1"},
+			wantBackup: []string{"2.8.0"},
+		},
+		"provider kube version": {
+			kubeVersion: "v1.29.8-eks-a737599",
+			platform:    "EKS",
+			wantLatest:  "7.0.15-9",
+			wantMongod:  []string{"7.0.14-8", "7.0.15-9"},
+			wantBackup:  []string{"2.7.0"},
+		},
+		"platform only": {
+			platform:   "openshift",
+			wantLatest: "7.0.15-9",
+			wantMongod: []string{"7.0.14-8", "7.0.15-9"},
+			wantBackup: []string{"2.7.0"},
+		},
+		"kube version only": {
+			kubeVersion:  "1.33.1",
+			wantLatest:   "8.0.4-1",
+			wantMongod:   []string{"8.0.4-1"},
+			wantBackup:   []string{},
+			wantWarnings: []string{"no backup version of operator 1.0.0 supports Kubernetes 1.33.1"},
+		},
+		"no compatible version of enabled component": {
+			kubeVersion:  "1.33.1",
+			backups:      true,
+			code:         codes.FailedPrecondition,
+			operatorCode: codes.OK,
+		},
+		"no compatible version of running component": {
+			kubeVersion:   "1.33.1",
+			backupVersion: "2.8.0",
+			code:          codes.FailedPrecondition,
+			operatorCode:  codes.OK,
+		},
+		"operator does not support kube version": {
+			kubeVersion:  "1.27.3",
+			code:         codes.FailedPrecondition,
+			operatorCode: codes.FailedPrecondition,
+		},
+		"operator does not support platform": {
+			platform:     "aks",
+			code:         codes.FailedPrecondition,
+			operatorCode: codes.FailedPrecondition,
+		},
+		"no compatible database version": {
+			kubeVersion:  "1.33.1",
+			platform:     "openshift",
+			code:         codes.FailedPrecondition,
+			operatorCode: codes.FailedPrecondition,
+		},
+		"invalid kube version": {
+			kubeVersion:  "one",
+			code:         codes.InvalidArgument,
+			operatorCode: codes.InvalidArgument,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			applied, err := b.Apply(ctx, &pbVersion.ApplyRequest{
+				Product:         "psmdb-operator",
+				OperatorVersion: "1.0.0",
+				Apply:           "latest",
+				KubeVersion:     tt.kubeVersion,
+				Platform:        tt.platform,
+				BackupVersion:   tt.backupVersion,
+				BackupsEnabled:  tt.backups,
+			})
+			resp, operatorErr := b.Operator(ctx, &pbVersion.OperatorRequest{
+				Product:         "psmdb-operator",
+				OperatorVersion: "1.0.0",
+				KubeVersion:     tt.kubeVersion,
+				Platform:        tt.platform,
+			})
+			assert.Equal(t, tt.operatorCode, status.Code(operatorErr))
+			if tt.code != codes.OK {
+				assert.Equal(t, tt.code, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []string{tt.wantLatest}, slices.Collect(maps.Keys(applied.Versions[0].Matrix.Mongod)))
+			assert.ElementsMatch(t, tt.wantBackup, slices.Collect(maps.Keys(applied.Versions[0].Matrix.Backup)))
+			assert.Equal(t, tt.wantWarnings, applied.Warnings)
+			assert.ElementsMatch(t, tt.wantMongod, slices.Collect(maps.Keys(resp.Versions[0].Matrix.Mongod)))
+		})
+	}
+}
+
+func TestValidateKubeVersions(t *testing.T) {
+	t.Parallel()
+
+	sources := MemorySourceStore{
+		"operator.1.0.0.psmdb-operator.json": []byte(`{
+  "versions": [
+    {
+      "supported_kube_versions": "1.28+",
+      "matrix": {"mongod": {"8.0.4-1": {"status": "available", "supported_kube_versions": ">= one"}}}
+    }
+  ]
+}`),
+	}
+
+	assert.Equal(t, []string{"matrix.mongod.8.0.4-1.supported_kube_versions", "versions.0.supported_kube_versions"},
+		validationIssueKeys(t, sources))
+}
+
+func TestBackend_arch(t *testing.T) {
+	t.Parallel()
+
+	overlay := Layer{Sources: MemorySourceStore{
+		"operator.1.0.0.psmdb-operator.json": []byte(`{
+  "versions": [
+    {
+      "matrix": {
+        "mongod": {
+          "7.0.14-8": {"image_hash": "amd-7.0.14-8"},
+          "7.0.15-9": {"image_hash": "amd-7.0.15-9", "image_hash_arm64": "arm-7.0.15-9"},
+          "8.0.4-1": {"image_hash": "amd-8.0.4-1"}
+        },
+        "backup": {
+          "2.7.0": {"image_hash": "amd-2.7.0", "image_hash_arm64": "arm-2.7.0"},
+          "2.8.0": {"image_hash": "amd-2.8.0", "image_hash_arm64": "arm-2.8.0"}
+        },
+        "pmm": {
+          "2.44.0": {"image_hash": "amd-2.44.0"},
+          "3.1.0": {"image_hash": "amd-3.1.0", "image_hash_arm64": "arm-3.1.0"}
+        },
+        "operator": {
+          "1.0.0": {"image_hash": "amd-1.0.0", "image_hash_arm64": "arm-1.0.0"}
+        }
+      }
+    }
+  ]
+}`),
+	}}
+	b, err := NewLayered([]Layer{{Sources: testSources}, overlay})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		arch      string
+		apply     string
+		wantHash  string
+		wantArm64 string
+		wantPMM   []string
+		code      codes.Code
+	}{
+		"any": {
+			apply:     "latest",
+			wantHash:  "amd-8.0.4-1",
+			wantArm64: "",
+			wantPMM:   []string{"2.44.0", "3.1.0"},
+		},
+		"amd64": {
+			arch:     "amd64",
+			apply:    "latest",
+			wantHash: "amd-8.0.4-1",
+			wantPMM:  []string{"2.44.0", "3.1.0"},
+		},
+		"arm64": {
+			arch:      "ARM64",
+			apply:     "latest",
+			wantHash:  "arm-7.0.15-9",
+			wantArm64: "arm-7.0.15-9",
+			wantPMM:   []string{"3.1.0"},
+		},
+		"multi": {
+			arch:      "multi",
+			apply:     "latest",
+			wantHash:  "amd-7.0.15-9",
+			wantArm64: "arm-7.0.15-9",
+			wantPMM:   []string{"3.1.0"},
+		},
+		"exact version without digest": {
+			arch:  "arm64",
+			apply: "8.0.4-1",
+			code:  codes.NotFound,
+		},
+		"invalid arch": {
+			arch:  "s390x",
+			apply: "latest",
+			code:  codes.InvalidArgument,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resp, err := b.Apply(context.Background(), &pbVersion.ApplyRequest{
+				Product:         "psmdb-operator",
+				OperatorVersion: "1.0.0",
+				Apply:           tt.apply,
+				Arch:            tt.arch,
+			})
+			if tt.code != codes.OK {
+				assert.Equal(t, tt.code, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+
+			m := resp.Versions[0].Matrix
+			require.Len(t, m.Mongod, 1)
+			for _, v := range m.Mongod {
+				assert.Equal(t, tt.wantHash, v.ImageHash)
+				assert.Equal(t, tt.wantArm64, v.ImageHashArm64)
+			}
+			assert.ElementsMatch(t, tt.wantPMM, slices.Collect(maps.Keys(m.Pmm)))
+		})
+	}
+}
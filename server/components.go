@@ -0,0 +1,481 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+)
+
+// componentsSource is the base name of the source file declaring components of operator products.
+const componentsSource = "components"
+
+// ComponentRegistry declares components of operator products keyed by product, e.g. psmdb-operator.
+// Apply uses it to select a single version of every component.
+type ComponentRegistry map[string]ProductComponents
+
+// ProductComponents declares the database and the other components of an operator product.
+type ProductComponents struct {
+	// Database is the matrix component holding database versions, e.g. mongod.
+	Database string `json:"database"`
+	// Policy names the selection policy of the database version, e.g. same-minor-highest.
+	Policy     string      `json:"policy"`
+	Components []Component `json:"components"`
+	// Upgrades declares the lines of database versions each line can be upgraded to in order of preference,
+	// e.g. "8.0": ["8.4"]. Lines are major.minor versions or major versions for policies selecting
+	// within a major.
+	Upgrades map[string][]string `json:"upgrades,omitempty"`
+}
+
+// upgradeLines returns the lines the line of database version v can be upgraded to.
+func (pc ProductComponents) upgradeLines(v string) []string {
+	cv, err := semver.NewVersion(v)
+	if err != nil {
+		return nil
+	}
+	p := selectionPolicies[pc.Policy]
+	for _, line := range slices.Sorted(maps.Keys(pc.Upgrades)) {
+		lv, err := semver.NewVersion(line)
+		if err == nil && p.sameLine(lv, cv) {
+			return pc.Upgrades[line]
+		}
+	}
+	return nil
+}
+
+// filterNextLine selects the highest version, recommended one for recommended, of the first line
+// the line of current can be upgraded to. Without such a line, it selects the version the policy does.
+func (pc ProductComponents) filterNextLine(versions map[string]*pbVersion.Version, apply string, current string, t *componentTrace) error {
+	p := selectionPolicies[pc.Policy]
+	if apply != recommended && apply != latest {
+		return status.Errorf(codes.InvalidArgument, "invalid apply option: %s%s", nextLinePrefix, apply)
+	}
+
+	for _, line := range pc.upgradeLines(current) {
+		lv, err := semver.NewVersion(line)
+		if err != nil {
+			continue
+		}
+		var best *semver.Version
+		for k, v := range versions {
+			sv, err := semver.NewVersion(k)
+			if err != nil || v.Status == pbVersion.Status_disabled || !p.sameLine(sv, lv) ||
+				(apply == recommended && v.Status != pbVersion.Status_recommended) {
+				continue
+			}
+			if best == nil || sv.GreaterThan(best) {
+				best = sv
+			}
+		}
+		if best != nil {
+			for k, v := range versions {
+				sv, err := semver.NewVersion(k)
+				switch {
+				case err != nil || !p.sameLine(sv, lv):
+					t.drop(k, "not on upgrade line %s of current version %s", line, current)
+				case v.Status == pbVersion.Status_disabled:
+					t.drop(k, "status is disabled")
+				case apply == recommended && v.Status != pbVersion.Status_recommended:
+					t.drop(k, "status is %s, not recommended", v.Status)
+				}
+			}
+			return keepVersion(best.Original(), versions)
+		}
+	}
+
+	return p.filter(versions, apply, current, t)
+}
+
+// Component declares how a version of a component is selected for the selected database version.
+type Component struct {
+	// Name is the matrix component, e.g. backup. Components without a dedicated
+	// VersionMatrix field are returned in its components field.
+	Name string `json:"name"`
+	// Deps is the key of the component rules in dependency source files, defaults to Name.
+	Deps string `json:"deps,omitempty"`
+	// Selector names the rule selecting the version, one of dep, pg_dep or pmm.
+	Selector string `json:"selector"`
+	// PrereleaseIsHigher sorts versions with the same major, minor and patch by their prerelease
+	// instead of considering prerelease versions lower when the highest version is selected.
+	PrereleaseIsHigher bool `json:"prerelease_is_higher,omitempty"`
+	// EOL lists end-of-life major versions of the component, Apply warns when it selects one of them.
+	EOL []string `json:"eol,omitempty"`
+}
+
+func (c Component) depsKey() string {
+	if c.Deps != "" {
+		return c.Deps
+	}
+	return c.Name
+}
+
+// nextLinePrefix of the apply option, e.g. major-recommended, selects the version on the next line
+// the database can be upgraded to instead of the version on its current line.
+const nextLinePrefix = "major-"
+
+// componentSelectors select the component version matching the database version
+// and record the reasons for dropping the other versions in t.
+var componentSelectors = map[string]func(c Component, versions map[string]*pbVersion.Version, rules *depRules, productVersion string, t *componentTrace) error{
+	"dep": func(c Component, versions map[string]*pbVersion.Version, rules *depRules, productVersion string, t *componentTrace) error {
+		v, err := rules.selected(versions, productVersion, false)
+		if err != nil {
+			return err
+		}
+		if err := explainDeps(t, versions, rules, productVersion); err != nil {
+			return err
+		}
+		return defaultFilter(versions, v, !c.PrereleaseIsHigher)
+	},
+	"pg_dep": func(c Component, versions map[string]*pbVersion.Version, rules *depRules, productVersion string, t *componentTrace) error {
+		v, err := rules.selected(versions, productVersion, true)
+		if err != nil {
+			return err
+		}
+		if err := explainDeps(t, versions, rules, productVersion); err != nil {
+			return err
+		}
+		return defaultFilter(versions, v, !c.PrereleaseIsHigher)
+	},
+	"pmm": func(c Component, versions map[string]*pbVersion.Version, _ *depRules, _ string, _ *componentTrace) error {
+		return pmmFilter(versions, !c.PrereleaseIsHigher)
+	},
+}
+
+// unmarshalComponentRegistry decodes a components source file in JSON or YAML format.
+func unmarshalComponentRegistry(content []byte, ext string) (ComponentRegistry, error) {
+	if isYAMLSource(ext) {
+		c, err := yamlToJSON(content)
+		if err != nil {
+			return nil, err
+		}
+		content = c
+	}
+
+	r := ComponentRegistry{}
+	err := json.Unmarshal(content, &r)
+	return r, err
+}
+
+// isComponentsSource reports whether name is a components source file and returns its extension.
+func isComponentsSource(name string) (string, bool) {
+	ext, ok := strings.CutPrefix(name, componentsSource)
+	if !ok || !slices.Contains([]string{sourceExt, ".yaml", ".yml"}, ext) {
+		return "", false
+	}
+	return ext, true
+}
+
+func validateComponentRegistry(file string, r ComponentRegistry, report *ValidationReport) {
+	for _, product := range slices.Sorted(maps.Keys(r)) {
+		pc := r[product]
+		if pc.Database == "" {
+			report.add(file, product+".database", "missing database component")
+		}
+		if _, ok := selectionPolicies[pc.Policy]; !ok {
+			report.add(file, product+".policy", "unknown policy %q", pc.Policy)
+		}
+		for _, line := range slices.Sorted(maps.Keys(pc.Upgrades)) {
+			key := fmt.Sprintf("%s.upgrades.%s", product, line)
+			from, err := semver.NewVersion(line)
+			if err != nil || !versionLineRegexp.MatchString(line) {
+				report.add(file, key, "invalid version line")
+				continue
+			}
+			for _, next := range pc.Upgrades[line] {
+				to, err := semver.NewVersion(next)
+				if err != nil || !versionLineRegexp.MatchString(next) {
+					report.add(file, key, "invalid version line %q", next)
+					continue
+				}
+				if !to.GreaterThan(from) {
+					report.add(file, key, "version line %s is not newer", next)
+				}
+			}
+		}
+		for i, c := range pc.Components {
+			key := fmt.Sprintf("%s.components.%d", product, i)
+			if c.Name == "" {
+				report.add(file, key, "missing component name")
+			}
+			if _, ok := componentSelectors[c.Selector]; !ok {
+				report.add(file, key, "unknown selector %q", c.Selector)
+			}
+			for _, major := range c.EOL {
+				if _, err := strconv.ParseUint(major, 10, 64); err != nil {
+					report.add(file, key+".eol", "invalid major version %q", major)
+				}
+			}
+		}
+	}
+}
+
+// apply selects the database version and a single version of every component of the product in vs
+// and records the reasons for dropping the other versions in e.
+func (r ComponentRegistry) apply(vs *pbVersion.VersionResponse, deps indexedDeps, req *pbVersion.ApplyRequest, e *explanation) error {
+	pc, ok := r[req.Product]
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "invalid product: %s", req.Product)
+	}
+	if len(vs.Versions) == 0 || vs.Versions[0].Matrix == nil {
+		return status.Error(codes.Internal, "no versions to filter")
+	}
+	m := vs.Versions[0].Matrix
+
+	productVersion := ""
+	err := filterComponent(m, pc.Database, func(versions map[string]*pbVersion.Version) error {
+		var err error
+		t := e.component(pc.Database)
+		if apply, ok := strings.CutPrefix(strings.ToLower(req.Apply), nextLinePrefix); ok {
+			err = pc.filterNextLine(versions, apply, req.DatabaseVersion, t)
+		} else {
+			err = selectionPolicies[pc.Policy].filter(versions, req.Apply, req.DatabaseVersion, t)
+		}
+		if err != nil {
+			return err
+		}
+		for k := range versions {
+			productVersion = k
+			break
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	pins := pinnedVersions(req)
+	for _, c := range pc.Components {
+		err := filterComponent(m, c.Name, func(versions map[string]*pbVersion.Version) error {
+			rules, t := deps[c.depsKey()], e.component(c.Name)
+			major, ok := pmmServerMajor(req)
+			serverMajor := ok && c.Name == "pmm" && len(versions) > 0
+			if serverMajor {
+				warning, err := c.keepMajor(versions, major, t)
+				if err != nil {
+					return err
+				}
+				if warning != "" {
+					vs.Warnings = append(vs.Warnings, warning)
+				}
+			}
+			if pin, ok := pins[c.Name]; ok && len(versions) > 0 {
+				// the pinned client version takes precedence over the highest version of the server major
+				var highest []string
+				if serverMajor {
+					latest := maps.Clone(versions)
+					if err := componentSelectors[c.Selector](c, latest, rules, productVersion, nil); err != nil {
+						return err
+					}
+					highest = slices.Collect(maps.Keys(latest))
+				}
+				warning, err := c.pin(versions, rules, pc.Database, productVersion, pin, t)
+				if warning != "" {
+					vs.Warnings = append(vs.Warnings, warning)
+				}
+				if _, pinned := versions[pin]; pinned {
+					for _, k := range highest {
+						if k != pin {
+							t.override(k, "version %s is pinned over the highest version of server major %d", pin, major)
+						}
+					}
+				}
+				return err
+			}
+			return componentSelectors[c.Selector](c, versions, rules, productVersion, t)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pinnedVersions returns component versions running in the cluster keyed by matrix component.
+// PMM clients run only if PMM is enabled.
+func pinnedVersions(req *pbVersion.ApplyRequest) map[string]string {
+	pins := map[string]string{}
+	for component, v := range map[string]string{
+		"pmm":           req.PmmVersion,
+		"backup":        req.BackupVersion,
+		"proxysql":      req.ProxysqlVersion,
+		"haproxy":       req.HaproxyVersion,
+		"log_collector": req.LogCollectorVersion,
+	} {
+		if v != "" && (component != "pmm" || req.PmmEnabled) {
+			pins[component] = v
+		}
+	}
+	return pins
+}
+
+// pmmServerMajor returns the major version of the PMM server of the cluster. PMM clients connect only
+// to servers of their major version, so it is the major version of the running client if PMM is enabled.
+func pmmServerMajor(req *pbVersion.ApplyRequest) (int64, bool) {
+	if !req.PmmEnabled || req.PmmVersion == "" {
+		return 0, false
+	}
+	v, err := semver.NewVersion(req.PmmVersion)
+	if err != nil {
+		return 0, false
+	}
+	return v.Major(), true
+}
+
+// keepMajor deletes versions of other major versions and warns if the major version is end of life.
+func (c Component) keepMajor(versions map[string]*pbVersion.Version, major int64, t *componentTrace) (string, error) {
+	for k := range versions {
+		if v, err := semver.NewVersion(k); err != nil || v.Major() != major {
+			t.drop(k, "major version differs from server major version %d", major)
+			delete(versions, k)
+		}
+	}
+	if len(versions) == 0 {
+		return "", status.Errorf(codes.FailedPrecondition, "no %s version for server major version %d", c.Name, major)
+	}
+
+	if slices.Contains(c.EOL, strconv.FormatInt(major, 10)) {
+		return fmt.Sprintf("%s %d is end of life, upgrade the server and clients to a newer major version", c.Name, major), nil
+	}
+	return "", nil
+}
+
+// pin keeps the pinned version if it is not disabled and its dependency rule allows the product version
+// of the database. Otherwise it keeps the compatible version closest to the pinned one, the lowest newer
+// one if there is any, or the version the selector selects if none is compatible or the pinned version is
+// invalid, e.g. a custom image tag, and returns a warning.
+func (c Component) pin(versions map[string]*pbVersion.Version, rules *depRules, database, productVersion, pin string, t *componentTrace) (string, error) {
+	pv, err := semver.NewVersion(pin)
+	if err != nil {
+		if err := componentSelectors[c.Selector](c, versions, rules, productVersion, t); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("pinned %s version %s is invalid, selected %s", c.Name, pin, strings.Join(sortedKeysDesc(versions), ", ")), nil
+	}
+
+	var compatible []string
+	for k, v := range versions {
+		reason, err := rules.reason(k, productVersion)
+		if err != nil {
+			return "", err
+		}
+		if reason == "" && v.Status == pbVersion.Status_disabled {
+			reason = "status is disabled"
+		}
+		if reason != "" {
+			t.drop(k, "%s", reason)
+			continue
+		}
+		compatible = append(compatible, k)
+	}
+
+	if slices.Contains(compatible, pin) {
+		for k := range versions {
+			t.drop(k, "version %s is pinned", pin)
+		}
+		return "", keepVersion(pin, versions)
+	}
+
+	warning := fmt.Sprintf("pinned %s version %s is not compatible with %s %s", c.Name, pin, database, productVersion)
+	if v, ok := versions[pin]; !ok {
+		warning = fmt.Sprintf("pinned %s version %s is not available", c.Name, pin)
+	} else if v.Status == pbVersion.Status_disabled {
+		warning = fmt.Sprintf("pinned %s version %s is disabled", c.Name, pin)
+	}
+	if len(compatible) == 0 {
+		if err := componentSelectors[c.Selector](c, versions, rules, productVersion, t); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s and no version is compatible, selected %s", warning, strings.Join(sortedKeysDesc(versions), ", ")), nil
+	}
+
+	sorted, err := sortedVersionsDesc(compatible, !c.PrereleaseIsHigher)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to sort versions: %v", err)
+	}
+	// the lowest version newer than the pinned one or the highest older one
+	closest := sorted[0]
+	for _, v := range sorted {
+		if v.LessThan(pv) {
+			break
+		}
+		closest = v
+	}
+	for k := range versions {
+		t.drop(k, "closest compatible version to pinned version %s is selected", pin)
+	}
+	return fmt.Sprintf("%s, selected %s", warning, closest.Original()), keepVersion(closest.Original(), versions)
+}
+
+var versionDescriptor = (&pbVersion.Version{}).ProtoReflect().Descriptor()
+
+// matrixField returns the VersionMatrix field of the component
+// or nil if the component does not have a dedicated field.
+func matrixField(component string) protoreflect.FieldDescriptor {
+	fd := (&pbVersion.VersionMatrix{}).ProtoReflect().Descriptor().Fields().ByName(protoreflect.Name(component))
+	if fd == nil || !fd.IsMap() || fd.MapValue().Message() != versionDescriptor {
+		return nil
+	}
+	return fd
+}
+
+// componentVersions returns versions of the matrix component.
+// The returned map is a copy, but the versions are shared with the matrix.
+func componentVersions(m *pbVersion.VersionMatrix, component string) map[string]*pbVersion.Version {
+	fd := matrixField(component)
+	if fd == nil {
+		return maps.Clone(m.GetComponents()[component].GetVersions())
+	}
+
+	mv := m.ProtoReflect().Get(fd).Map()
+	versions := make(map[string]*pbVersion.Version, mv.Len())
+	mv.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+		versions[k.String()] = v.Message().Interface().(*pbVersion.Version)
+		return true
+	})
+	return versions
+}
+
+// filterComponent calls fn with versions of the matrix component and removes
+// the versions fn deleted from the matrix.
+func filterComponent(m *pbVersion.VersionMatrix, component string, fn func(versions map[string]*pbVersion.Version) error) error {
+	fd := matrixField(component)
+	if fd == nil {
+		var versions map[string]*pbVersion.Version
+		if c := m.Components[component]; c != nil {
+			versions = c.Versions
+		}
+		return fn(versions)
+	}
+
+	pm := m.ProtoReflect()
+	if !pm.Has(fd) {
+		return fn(nil)
+	}
+	versions := componentVersions(m, component)
+	if err := fn(versions); err != nil {
+		return err
+	}
+
+	mv := pm.Mutable(fd).Map()
+	var removed []protoreflect.MapKey
+	mv.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+		if _, ok := versions[k.String()]; !ok {
+			removed = append(removed, k)
+		}
+		return true
+	})
+	for _, k := range removed {
+		mv.Clear(k)
+	}
+	return nil
+}
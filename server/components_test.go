@@ -0,0 +1,271 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+)
+
+func TestComponentRegistry_sidecar(t *testing.T) {
+	t.Parallel()
+
+	// the sidecar has neither a VersionMatrix field nor a Deps field
+	overlay := Layer{
+		Name: "overlay",
+		Sources: MemorySourceStore{
+			"components.yaml": []byte(`
+psmdb-operator:
+  database: mongod
+  policy: same-minor-highest
+  components:
+    - name: backup
+      selector: dep
+    - name: logrotate
+      selector: dep
+`),
+			"operator.1.0.0.psmdb-operator.json": []byte(`{
+  "versions": [
+    {
+      "matrix": {
+        "components": {
+          "logrotate": {
+            "versions": {
+              "1.0.0": {"image_path": "percona/logrotate:1.0.0", "status": "recommended"},
+              "1.1.0": {"image_path": "percona/logrotate:1.1.0", "status": "recommended"}
+            }
+          }
+        }
+      }
+    }
+  ]
+}`),
+			"operator.1.0.0.psmdb-operator.dep.json": []byte(`{
+  "logrotate": {
+    "1.1.0": {">=": [{"var": "productVersion"}, "8.0"]},
+    "1.0.0": {"<": [{"var": "productVersion"}, "8.0"]}
+  }
+}`),
+		},
+	}
+
+	b, err := NewLayered([]Layer{{Sources: testSources}, overlay})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		apply         string
+		wantLogrotate string
+		wantBackup    string
+	}{
+		"recommended": {apply: "recommended", wantLogrotate: "1.0.0", wantBackup: "2.7.0"},
+		"latest":      {apply: "latest", wantLogrotate: "1.1.0", wantBackup: "2.8.0"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resp, err := b.Apply(context.Background(), &pbVersion.ApplyRequest{
+				Product:         "psmdb-operator",
+				OperatorVersion: "1.0.0",
+				Apply:           tt.apply,
+			})
+			require.NoError(t, err)
+			m := resp.Versions[0].Matrix
+			assert.Equal(t, []string{tt.wantLogrotate}, slices.Collect(maps.Keys(m.Components["logrotate"].Versions)))
+			assert.Equal(t, []string{tt.wantBackup}, slices.Collect(maps.Keys(m.Backup)))
+			// pmm is not declared in the overlay registry, so it is not filtered anymore
+			assert.Len(t, m.Pmm, 2)
+		})
+	}
+}
+
+func TestComponentRegistry_pin(t *testing.T) {
+	t.Parallel()
+
+	b, err := New(testSources, fstest.MapFS{}, fstest.MapFS{})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		apply       string
+		backup      string
+		pmm         string
+		pmmEnabled  bool
+		wantBackup  string
+		wantPMM     []string
+		wantWarning []string
+		code        codes.Code
+	}{
+		"not pinned": {
+			apply:      "8.0.4-1",
+			wantBackup: "2.8.0",
+			wantPMM:    []string{"2.44.0", "3.1.0"},
+		},
+		"compatible": {
+			apply:       "7.0.15-9",
+			backup:      "2.7.0",
+			pmm:         "2.44.0",
+			pmmEnabled:  true,
+			wantBackup:  "2.7.0",
+			wantPMM:     []string{"2.44.0"},
+			wantWarning: []string{"pmm 2 is end of life, upgrade the server and clients to a newer major version"},
+		},
+		"pmm disabled": {
+			apply:      "7.0.15-9",
+			pmm:        "2.44.0",
+			wantBackup: "2.7.0",
+			wantPMM:    []string{"2.44.0", "3.1.0"},
+		},
+		"not compatible": {
+			apply:       "8.0.4-1",
+			backup:      "2.7.0",
+			wantBackup:  "2.8.0",
+			wantPMM:     []string{"2.44.0", "3.1.0"},
+			wantWarning: []string{"pinned backup version 2.7.0 is not compatible with mongod 8.0.4-1, selected 2.8.0"},
+		},
+		"not available": {
+			apply:      "7.0.15-9",
+			backup:     "2.7.5",
+			pmm:        "3.0.0",
+			pmmEnabled: true,
+			wantBackup: "2.7.0",
+			wantPMM:    []string{"3.1.0"},
+			wantWarning: []string{
+				"pinned backup version 2.7.5 is not available, selected 2.7.0",
+				"pinned pmm version 3.0.0 is not available, selected 3.1.0",
+			},
+		},
+		"no version of server major": {
+			apply:      "7.0.15-9",
+			pmm:        "1.17.0",
+			pmmEnabled: true,
+			code:       codes.FailedPrecondition,
+		},
+		"invalid": {
+			apply:       "8.0.4-1",
+			backup:      "two",
+			wantBackup:  "2.8.0",
+			wantPMM:     []string{"2.44.0", "3.1.0"},
+			wantWarning: []string{"pinned backup version two is invalid, selected 2.8.0"},
+		},
+		"invalid pmm": {
+			apply:       "7.0.15-9",
+			pmm:         "custom",
+			pmmEnabled:  true,
+			wantBackup:  "2.7.0",
+			wantPMM:     []string{"2.44.0", "3.1.0"},
+			wantWarning: []string{"pinned pmm version custom is invalid, selected 3.1.0, 2.44.0"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resp, err := b.Apply(context.Background(), &pbVersion.ApplyRequest{
+				Product:         "psmdb-operator",
+				OperatorVersion: "1.0.0",
+				Apply:           tt.apply,
+				BackupVersion:   tt.backup,
+				PmmVersion:      tt.pmm,
+				PmmEnabled:      tt.pmmEnabled,
+			})
+			if tt.code != codes.OK {
+				assert.Equal(t, tt.code, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+
+			matrix := resp.Versions[0].Matrix
+			assert.Equal(t, []string{tt.wantBackup}, slices.Collect(maps.Keys(matrix.Backup)))
+			assert.ElementsMatch(t, tt.wantPMM, slices.Collect(maps.Keys(matrix.Pmm)))
+			assert.Equal(t, tt.wantWarning, resp.Warnings)
+		})
+	}
+}
+
+func TestComponentRegistry_pinOverServerMajor(t *testing.T) {
+	t.Parallel()
+
+	overlay := Layer{Sources: MemorySourceStore{
+		"operator.1.0.0.psmdb-operator.json": []byte(`{
+  "versions": [
+    {
+      "matrix": {
+        "pmm": {"3.0.0": {"image_path": "percona/pmm-client:3.0.0", "status": "recommended"}}
+      }
+    }
+  ]
+}`),
+	}}
+	b, err := NewLayered([]Layer{{Sources: testSources}, overlay})
+	require.NoError(t, err)
+
+	resp, err := b.Apply(context.Background(), &pbVersion.ApplyRequest{
+		Product:         "psmdb-operator",
+		OperatorVersion: "1.0.0",
+		Apply:           "7.0.15-9",
+		PmmVersion:      "3.0.0",
+		PmmEnabled:      true,
+		Explain:         true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3.0.0"}, slices.Collect(maps.Keys(resp.Versions[0].Matrix.Pmm)))
+	assert.Empty(t, resp.Warnings)
+
+	reasons := map[string]string{}
+	for _, c := range resp.Explanation {
+		for _, v := range c.Candidates {
+			if c.Component == "pmm" {
+				reasons[v.Version] = v.Reason
+			}
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"2.44.0": "major version differs from server major version 3",
+		"3.0.0":  "",
+		"3.1.0":  "version 3.0.0 is pinned over the highest version of server major 3",
+	}, reasons)
+}
+
+func TestValidateComponentRegistry(t *testing.T) {
+	t.Parallel()
+
+	sources := MemorySourceStore{
+		"components.json": []byte(`{
+  "psmdb-operator": {
+    "policy": "mongo",
+    "components": [{"name": "backup", "selector": "dep"}, {"selector": "latest"}, {"name": "pmm", "selector": "pmm", "eol": ["v2"]}],
+    "upgrades": {"7.0": ["8.0", "6.0", "eight"], "8": ["9"]}
+  }
+}`),
+		"components.yaml": []byte("{}"),
+	}
+
+	_, err := New(sources, fstest.MapFS{}, fstest.MapFS{})
+	var report *ValidationReport
+	require.True(t, errors.As(err, &report))
+
+	type issue struct{ file, key string }
+	got := make([]issue, 0, len(report.Issues))
+	for _, i := range report.Issues {
+		got = append(got, issue{i.File, i.Key})
+	}
+	assert.Equal(t, []issue{
+		{"components.json", "psmdb-operator.components.1"},
+		{"components.json", "psmdb-operator.components.1"},
+		{"components.json", "psmdb-operator.components.2.eol"},
+		{"components.json", "psmdb-operator.database"},
+		{"components.json", "psmdb-operator.policy"},
+		{"components.json", "psmdb-operator.upgrades.7.0"},
+		{"components.json", "psmdb-operator.upgrades.7.0"},
+		{"components.yaml", ""},
+	}, got)
+}
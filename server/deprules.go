@@ -0,0 +1,406 @@
+package server
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Masterminds/semver"
+	jsonlogicv2 "github.com/diegoholiveira/jsonlogic"
+	"github.com/diegoholiveira/jsonlogic/v3"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+)
+
+// semverOperators are jsonlogic operators comparing versions as semver instead of strings,
+// e.g. {"semver_gte": [{"var": "productVersion"}, "8.0.9"]}, keyed by the operators they replace.
+var semverOperators = map[string]string{
+	">":  "semver_gt",
+	">=": "semver_gte",
+	"<":  "semver_lt",
+	"<=": "semver_lte",
+	"==": "semver_eq",
+}
+
+func init() {
+	for _, op := range []struct {
+		name string
+		ok   func(c int) bool
+	}{
+		{"semver_gt", func(c int) bool { return c > 0 }},
+		{"semver_gte", func(c int) bool { return c >= 0 }},
+		{"semver_lt", func(c int) bool { return c < 0 }},
+		{"semver_lte", func(c int) bool { return c <= 0 }},
+		{"semver_eq", func(c int) bool { return c == 0 }},
+	} {
+		jsonlogic.AddOperator(op.name, semverCompare(op.ok))
+	}
+	jsonlogic.AddOperator("semver_satisfies", semverSatisfies)
+}
+
+// indexedDeps are the dependency rules of an operator version keyed by component.
+type indexedDeps map[string]*depRules
+
+// depRules are the dependency rules of a component with their versions sorted when sources are read.
+// The rules are evaluated as decoded, results are memoized per product version; product versions
+// are database versions of the sources, so the memo is bounded and is dropped with the sources on reload. A nil depRules has no rules.
+type depRules struct {
+	rules map[string]interface{}
+	// sorted holds the versions of the rules sorted in descending order.
+	sorted []*semver.Version
+	// results holds *depResult keyed by product version.
+	results sync.Map
+}
+
+// depResult is the result of the rules of a component for a product version.
+type depResult struct {
+	// allowed holds the versions whose rule is true.
+	allowed map[string]bool
+	// dep and pgDep are the versions selected by the dep and pg_dep selectors.
+	dep, pgDep string
+}
+
+// indexDeps sorts the versions of the dependency rules of an operator version.
+// Versions which are not valid semver are skipped, validation reports them.
+func indexDeps(deps Deps) indexedDeps {
+	res := make(indexedDeps, len(deps))
+	for component, rules := range deps {
+		r := &depRules{rules: rules, sorted: make([]*semver.Version, 0, len(rules))}
+		for k := range rules {
+			if v, err := semver.NewVersion(k); err == nil {
+				r.sorted = append(r.sorted, v)
+			}
+		}
+		slices.SortFunc(r.sorted, func(a, b *semver.Version) int { return b.Compare(a) })
+		res[component] = r
+	}
+	return res
+}
+
+// result returns the result of the rules for the product version.
+func (r *depRules) result(productVersion string) (*depResult, error) {
+	if r == nil || len(r.rules) == 0 {
+		return &depResult{}, nil
+	}
+	if res, ok := r.results.Load(productVersion); ok {
+		return res.(*depResult), nil
+	}
+
+	res := &depResult{allowed: make(map[string]bool, len(r.rules))}
+	for k, rule := range r.rules {
+		ok, err := depRuleTrue(rule, productVersion)
+		if err != nil {
+			return nil, err
+		}
+		res.allowed[k] = ok
+	}
+	if len(r.sorted) > 0 {
+		res.dep, res.pgDep = r.sorted[0].String(), r.sorted[0].String()
+	}
+	// the dep selector picks the highest allowed version, pg_dep does the same for
+	// versions without a patch version, e.g. 16 or 14.23
+	for _, v := range r.sorted {
+		if res.allowed[v.String()] {
+			res.dep = v.String()
+			break
+		}
+	}
+	for _, v := range r.sorted {
+		if res.allowed[strings.TrimSuffix(v.String(), ".0")] {
+			res.pgDep = v.String()
+			break
+		}
+	}
+
+	actual, _ := r.results.LoadOrStore(productVersion, res)
+	return actual.(*depResult), nil
+}
+
+// selected returns the version the dep or, with pg, the pg_dep selector selects: the memoized one or, if it
+// is not one of versions, e.g. because it is withdrawn, the highest allowed version left in versions.
+func (r *depRules) selected(versions map[string]*pbVersion.Version, productVersion string, pg bool) (string, error) {
+	res, err := r.result(productVersion)
+	if err != nil {
+		return "", err
+	}
+	v := res.dep
+	if pg {
+		v = res.pgDep
+	}
+	if v == "" || hasRelease(versions, v) {
+		return v, nil
+	}
+	for _, sv := range r.sorted {
+		k := sv.String()
+		if pg {
+			k = strings.TrimSuffix(k, ".0")
+		}
+		if res.allowed[k] && hasRelease(versions, sv.String()) {
+			return sv.String(), nil
+		}
+	}
+	return v, nil
+}
+
+// hasRelease reports whether versions has a version with the major.minor.patch of v.
+func hasRelease(versions map[string]*pbVersion.Version, v string) bool {
+	if _, ok := versions[v]; ok {
+		return true
+	}
+	sv, err := semver.NewVersion(v)
+	if err != nil {
+		return false
+	}
+	for k := range versions {
+		if kv, err := semver.NewVersion(k); err == nil && releaseVersion(kv).Equal(releaseVersion(sv)) {
+			return true
+		}
+	}
+	return false
+}
+
+// reason returns why the rules do not allow version v with the product version
+// or an empty string if they do. Without rules every version is allowed.
+func (r *depRules) reason(v string, productVersion string) (string, error) {
+	if r == nil || len(r.rules) == 0 {
+		return "", nil
+	}
+	if _, ok := r.rules[v]; !ok {
+		return "no dependency rule", nil
+	}
+	res, err := r.result(productVersion)
+	if err != nil || res.allowed[v] {
+		return "", err
+	}
+	return fmt.Sprintf("dependency rule is false for product version %s", productVersion), nil
+}
+
+// releaseVersion returns major.minor.patch of v.
+func releaseVersion(v *semver.Version) *semver.Version {
+	return semver.MustParse(fmt.Sprintf("%d.%d.%d", v.Major(), v.Minor(), v.Patch()))
+}
+
+// compareVersions compares versions by major.minor.patch. Suffixes of database versions, e.g. -28
+// of 8.0.36-28, are build numbers rather than prereleases, so they are compared only if both have one.
+func compareVersions(a, b *semver.Version) int {
+	c := releaseVersion(a).Compare(releaseVersion(b))
+	if c != 0 || a.Prerelease() == "" || b.Prerelease() == "" {
+		return c
+	}
+	return a.Compare(b)
+}
+
+// semverCompare returns an operator comparing two versions or, like the jsonlogic comparison
+// operators, checking three versions for between. Invalid versions are never true.
+func semverCompare(ok func(c int) bool) func(values, data interface{}) interface{} {
+	return func(values, _ interface{}) interface{} {
+		args, _ := values.([]interface{})
+		if len(args) < 2 || len(args) > 3 {
+			return false
+		}
+		versions := make([]*semver.Version, 0, len(args))
+		for _, a := range args {
+			v, err := semver.NewVersion(fmt.Sprint(a))
+			if err != nil {
+				return false
+			}
+			versions = append(versions, v)
+		}
+		for i := 1; i < len(versions); i++ {
+			if !ok(compareVersions(versions[i-1], versions[i])) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// semverSatisfies checks a version against a range, e.g. {"semver_satisfies": [{"var": "productVersion"}, "~8.0.36"]}.
+// Only major.minor.patch of the version is checked. Invalid versions and ranges are never satisfied.
+func semverSatisfies(values, _ interface{}) interface{} {
+	args, _ := values.([]interface{})
+	if len(args) != 2 {
+		return false
+	}
+	v, err := semver.NewVersion(fmt.Sprint(args[0]))
+	if err != nil {
+		return false
+	}
+	c, err := parseRange(fmt.Sprint(args[1]))
+	if err != nil {
+		return false
+	}
+	return c.Check(releaseVersion(v))
+}
+
+// validateSemverOperands checks that literal operands of semver operators in rule are valid
+// versions and ranges, as the operators are false for invalid ones instead of failing.
+func validateSemverOperands(rule interface{}) error {
+	switch r := rule.(type) {
+	case []interface{}:
+		for _, v := range r {
+			if err := validateSemverOperands(v); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		for op, values := range r {
+			args, isArgs := values.([]interface{})
+			switch {
+			case op == "semver_satisfies" && isArgs && len(args) == 2:
+				if s, ok := args[1].(string); ok {
+					if _, err := parseRange(s); err != nil {
+						return fmt.Errorf("invalid range %q: %w", s, err)
+					}
+				}
+			case slices.Contains(slices.Collect(maps.Values(semverOperators)), op) && isArgs:
+				for _, a := range args {
+					if s, ok := a.(string); ok {
+						if _, err := semver.NewVersion(s); err != nil {
+							return fmt.Errorf("invalid version %q: %w", s, err)
+						}
+					}
+				}
+			}
+			if err := validateSemverOperands(values); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// semverRule returns a copy of rule with comparisons of productVersion replaced by semver operators.
+func semverRule(rule interface{}) interface{} {
+	switch r := rule.(type) {
+	case []interface{}:
+		res := make([]interface{}, 0, len(r))
+		for _, v := range r {
+			res = append(res, semverRule(v))
+		}
+		return res
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(r))
+		for op, values := range r {
+			if semverOp, ok := semverOperators[op]; ok && comparesProductVersion(values) {
+				op = semverOp
+			}
+			res[op] = semverRule(values)
+		}
+		return res
+	}
+	return rule
+}
+
+// comparesProductVersion reports whether productVersion is one of the operands.
+func comparesProductVersion(values interface{}) bool {
+	args, _ := values.([]interface{})
+	return slices.ContainsFunc(args, func(a interface{}) bool {
+		m, ok := a.(map[string]interface{})
+		return ok && m["var"] == "productVersion"
+	})
+}
+
+// usesSemverOperators reports whether the rule uses any of the semver operators.
+func usesSemverOperators(rule interface{}) bool {
+	switch r := rule.(type) {
+	case []interface{}:
+		return slices.ContainsFunc(r, usesSemverOperators)
+	case map[string]interface{}:
+		for op, values := range r {
+			if strings.HasPrefix(op, "semver_") || usesSemverOperators(values) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// depRuleTrueV2 reports whether the dependency rule is true for the product version with jsonlogic v2,
+// which evaluated dependency rules before v3. The rule is copied, as v2 may modify it.
+func depRuleTrueV2(rule interface{}, productVersion string) (_ bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to apply logic: %v", r)
+		}
+	}()
+
+	b, err := json.Marshal(rule)
+	if err != nil {
+		return false, err
+	}
+	var c interface{}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return false, err
+	}
+	res, err := jsonlogicv2.ApplyInterface(c, map[string]interface{}{"productVersion": productVersion})
+	if err != nil {
+		return false, fmt.Errorf("failed to apply logic: %w", err)
+	}
+	return res == true, nil
+}
+
+// CheckDepRules reports dependency rules of the sources whose results for the database versions
+// of their operator versions change if comparisons of productVersion use semver operators instead of
+// comparing strings, e.g. "8.0.9" >= "8.0.36", and rules without semver operators whose results
+// differ between jsonlogic v2, which evaluated them before, and v3. Sources must be valid.
+func CheckDepRules(layers []Layer) (*ValidationReport, error) {
+	report := &ValidationReport{}
+	s, err := readSources(layers, report)
+	if err != nil {
+		return nil, err
+	}
+	if err := report.err(); err != nil {
+		return nil, err
+	}
+
+	for _, key := range slices.SortedFunc(maps.Keys(s.deps), func(a, b sourceKey) int {
+		return cmp.Compare(a.fileName(depSourceExt), b.fileName(depSourceExt))
+	}) {
+		pc, ok := s.components[key.product]
+		vs, hasVersions := s.versions[key]
+		if !ok || !hasVersions || len(vs.Versions) == 0 || vs.Versions[0].Matrix == nil {
+			continue
+		}
+		databaseVersions := sortedKeysDesc(componentVersions(vs.Versions[0].Matrix, pc.Database))
+
+		deps := s.deps[key]
+		for _, component := range slices.Sorted(maps.Keys(deps)) {
+			for _, v := range slices.Sorted(maps.Keys(deps[component])) {
+				rule := deps[component][v]
+				migrated := semverRule(rule)
+				for _, dv := range databaseVersions {
+					before, err := depRuleTrue(rule, dv)
+					if err != nil {
+						return nil, err
+					}
+					after, err := depRuleTrue(migrated, dv)
+					if err != nil {
+						return nil, err
+					}
+					if before != after {
+						report.add(s.depFiles[key][component+"."+v], component+"."+v,
+							"rule is %t for %s %s, %t with semver operators", before, pc.Database, dv, after)
+					}
+					if usesSemverOperators(rule) {
+						continue
+					}
+					v2, err := depRuleTrueV2(rule, dv)
+					if err != nil {
+						report.add(s.depFiles[key][component+"."+v], component+"."+v,
+							"rule fails for %s %s with jsonlogic v2: %v", pc.Database, dv, err)
+					} else if v2 != before {
+						report.add(s.depFiles[key][component+"."+v], component+"."+v,
+							"rule is %t for %s %s, %t with jsonlogic v2", before, pc.Database, dv, v2)
+					}
+				}
+			}
+		}
+	}
+
+	return report, nil
+}
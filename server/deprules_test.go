@@ -0,0 +1,224 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/diegoholiveira/jsonlogic/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSemverOperators(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		rule           string
+		productVersion string
+		want           bool
+	}{
+		"gte":                      {`{"semver_gte": [{"var": "productVersion"}, "8.0.36"]}`, "8.0.36-28", true},
+		"gte lexically lower":      {`{"semver_gte": [{"var": "productVersion"}, "8.0.9"]}`, "8.0.36-28", true},
+		"lt lexically higher":      {`{"semver_lt": [{"var": "productVersion"}, "8.0.36"]}`, "8.0.9", true},
+		"gt":                       {`{"semver_gt": [{"var": "productVersion"}, "8.0.36"]}`, "8.0.36-28", false},
+		"gt suffix":                {`{"semver_gt": [{"var": "productVersion"}, "8.0.36-27"]}`, "8.0.36-28", true},
+		"lte":                      {`{"semver_lte": [{"var": "productVersion"}, "16"]}`, "16", true},
+		"eq":                       {`{"semver_eq": [{"var": "productVersion"}, "17.10"]}`, "17.10.0", true},
+		"between":                  {`{"semver_lt": ["16", {"var": "productVersion"}, "17"]}`, "16.4", true},
+		"not between":              {`{"semver_lte": ["16", {"var": "productVersion"}, "17"]}`, "17.2", false},
+		"satisfies":                {`{"semver_satisfies": [{"var": "productVersion"}, "~8.0.36"]}`, "8.0.36-28", true},
+		"satisfies space range":    {`{"semver_satisfies": [{"var": "productVersion"}, ">= 8.0 < 8.4"]}`, "8.4.2-2", false},
+		"invalid version":          {`{"semver_gte": [{"var": "productVersion"}, "8.0"]}`, "latest", false},
+		"satisfies invalid range":  {`{"semver_satisfies": [{"var": "productVersion"}, "~foo"]}`, "8.0.36", false},
+		"combined with jsonlogic":  {`{"and": [{"semver_gte": [{"var": "productVersion"}, "8.0"]}, {"!": {"semver_eq": [{"var": "productVersion"}, "8.0.40"]}}]}`, "8.0.40", false},
+		"wrong number of operands": {`{"semver_gte": [{"var": "productVersion"}]}`, "8.0.36", false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var rule interface{}
+			require.NoError(t, json.Unmarshal([]byte(tt.rule), &rule))
+			got, err := depRuleTrue(rule, tt.productVersion)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDepRuleTrue_panic(t *testing.T) {
+	// not parallel, operators are registered globally
+	jsonlogic.AddOperator("test_panic", func(values, _ interface{}) interface{} {
+		panic(fmt.Sprintf("unexpected operands %v", values))
+	})
+
+	_, err := depRuleTrue(map[string]interface{}{"test_panic": []interface{}{1}}, "8.0.36")
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestDepRuleTrueV2(t *testing.T) {
+	t.Parallel()
+
+	// v3 is used since semver operators need custom operators, results of rules without them must not change
+	for _, r := range []string{
+		`{">=": [{"var": "productVersion"}, "8.0"]}`,
+		`{"<": [{"var": "productVersion"}, "8.0.36"]}`,
+		`{"and": [{">=": [{"var": "productVersion"}, "16"]}, {"<": [{"var": "productVersion"}, "17"]}]}`,
+		`{"or": [{"==": [{"var": "productVersion"}, "7.0.15-9"]}, {"!": {"<=": [{"var": "productVersion"}, "6.0"]}}]}`,
+		`{"in": [{"var": "productVersion"}, ["16.4", "17.2"]]}`,
+	} {
+		var rule interface{}
+		require.NoError(t, json.Unmarshal([]byte(r), &rule))
+		assert.False(t, usesSemverOperators(rule))
+		for _, pv := range []string{"5.7.44", "6.0.19-16", "8.0.9", "8.0.36-28", "16.4", "17.2"} {
+			v3, err := depRuleTrue(rule, pv)
+			require.NoError(t, err)
+			v2, err := depRuleTrueV2(rule, pv)
+			require.NoError(t, err)
+			assert.Equal(t, v3, v2, "%s for %s", r, pv)
+		}
+	}
+
+	var rule interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"!": {"semver_gte": [{"var": "productVersion"}, "8.0"]}}`), &rule))
+	assert.True(t, usesSemverOperators(rule))
+}
+
+func TestValidateSemverOperands(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		rule    string
+		wantErr bool
+	}{
+		"valid":           {rule: `{"semver_satisfies": [{"var": "productVersion"}, ">= 8.0 < 8.4"]}`},
+		"invalid version": {rule: `{"or": [{"semver_gte": [{"var": "productVersion"}, "eight"]}]}`, wantErr: true},
+		"invalid range":   {rule: `{"semver_satisfies": [{"var": "productVersion"}, "~foo"]}`, wantErr: true},
+		"string operator": {rule: `{">=": [{"var": "productVersion"}, "eight"]}`},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var rule interface{}
+			require.NoError(t, json.Unmarshal([]byte(tt.rule), &rule))
+			err := validateDepRule(rule)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCheckDepRules(t *testing.T) {
+	t.Parallel()
+
+	sources := MemorySourceStore{
+		"components.json": distributionTestSources["components.json"],
+		"operator.2.0.0.pg-operator.json": []byte(`{
+  "versions": [
+    {
+      "operator": "2.0.0",
+      "product": "pg-operator",
+      "matrix": {
+        "postgresql": {
+          "9.6": {"status": "recommended"},
+          "16.4": {"status": "recommended"}
+        },
+        "pgbouncer": {
+          "1.23.1": {"status": "recommended"},
+          "1.22.0": {"status": "recommended"}
+        }
+      }
+    }
+  ]
+}`),
+		"operator.2.0.0.pg-operator.dep.json": []byte(`{
+  "pgbouncer": {
+    "1.22.0": {"semver_lt": [{"var": "productVersion"}, "16"]}
+  }
+}`),
+	}
+	overlay := MemorySourceStore{
+		"operator.2.0.0.pg-operator.dep.yaml": []byte(`pgbouncer:
+  1.23.1:
+    ">=": [{var: productVersion}, "16"]
+`),
+	}
+
+	report, err := CheckDepRules([]Layer{{Sources: sources}, {Name: "overlay", Sources: overlay}})
+	require.NoError(t, err)
+
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, "overlay/operator.2.0.0.pg-operator.dep.yaml", report.Issues[0].File)
+	assert.Equal(t, "pgbouncer.1.23.1", report.Issues[0].Key)
+	assert.Equal(t, "rule is true for postgresql 9.6, false with semver operators", report.Issues[0].Reason)
+
+	invalid := MemorySourceStore{"operator.2.0.0.pg-operator.dep.json": []byte(`{"pgbouncer": {"1.23.1": {"semver_gte": ["x"]}}}`)}
+	_, err = CheckDepRules([]Layer{{Sources: invalid}})
+	var r *ValidationReport
+	assert.True(t, errors.As(err, &r))
+}
+
+func TestDepRules_result(t *testing.T) {
+	t.Parallel()
+
+	var deps Deps
+	require.NoError(t, json.Unmarshal([]byte(`{
+  "backup": {
+    "2.8.0": {">=": [{"var": "productVersion"}, "8.0"]},
+    "2.7.0": {"<": [{"var": "productVersion"}, "8.0"]}
+  },
+  "pgbackrest": {
+    "16.0": {"semver_satisfies": [{"var": "productVersion"}, "16.x"]},
+    "17.2": {"semver_satisfies": [{"var": "productVersion"}, "17.x"]}
+  },
+  "pgbouncer": {
+    "1.23.1": {"==": [{"var": "productVersion"}, "17.2\"1"]}
+  }
+}`), &deps))
+	rules := indexDeps(deps)
+
+	tests := map[string]struct {
+		component      string
+		productVersion string
+		wantDep        string
+		wantPgDep      string
+	}{
+		"dep":                  {component: "backup", productVersion: "8.0.4-1", wantDep: "2.8.0", wantPgDep: "2.8.0"},
+		"dep lower":            {component: "backup", productVersion: "7.0.15-9", wantDep: "2.7.0", wantPgDep: "2.8.0"},
+		"pg_dep without patch": {component: "pgbackrest", productVersion: "16.4", wantDep: "17.2.0", wantPgDep: "16.0.0"},
+		"quoted version":       {component: "pgbouncer", productVersion: `17.2"1`, wantDep: "1.23.1", wantPgDep: "1.23.1"},
+		"no rules":             {component: "haproxy", productVersion: "8.0.4-1"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r := rules[tt.component]
+			res, err := r.result(tt.productVersion)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantDep, res.dep)
+			assert.Equal(t, tt.wantPgDep, res.pgDep)
+
+			memoized, err := r.result(tt.productVersion)
+			require.NoError(t, err)
+			if r != nil {
+				assert.Same(t, res, memoized)
+			}
+		})
+	}
+
+	reason, err := rules["backup"].reason("2.8.0", "7.0.15-9")
+	require.NoError(t, err)
+	assert.Equal(t, "dependency rule is false for product version 7.0.15-9", reason)
+	reason, err = rules["backup"].reason("2.6.0", "7.0.15-9")
+	require.NoError(t, err)
+	assert.Equal(t, "no dependency rule", reason)
+}
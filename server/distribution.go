@@ -0,0 +1,81 @@
+package server
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+)
+
+// distributions are the distributions source files can declare images for.
+var distributions = []string{"percona", "community"}
+
+// distribution selects images of the distribution running in a cluster.
+// A nil distribution keeps the default images.
+type distribution struct {
+	name string
+	// missing holds versions without an image of the distribution keyed by component.
+	missing map[string][]string
+}
+
+// newDistribution returns the distribution of a request or nil if it is unknown.
+func newDistribution(name string) (*distribution, error) {
+	if name == "" {
+		return nil, nil
+	}
+	name = strings.ToLower(name)
+	if !slices.Contains(distributions, name) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid distribution: %s", name)
+	}
+	return &distribution{name: name, missing: map[string][]string{}}, nil
+}
+
+// selectImages replaces images of versions declaring distribution images with the images of the
+// distribution and remembers the versions without one. Versions without distribution images keep theirs.
+// The distribution images are removed from the versions.
+func (d *distribution) selectImages(vs *pbVersion.VersionResponse) {
+	if d == nil {
+		return
+	}
+
+	for _, ov := range vs.Versions {
+		if ov.Matrix == nil {
+			continue
+		}
+		rangeMatrix(ov.Matrix, func(component string, versions map[string]*pbVersion.Version) {
+			for k, v := range versions {
+				if len(v.Distributions) == 0 {
+					continue
+				}
+				img, ok := v.Distributions[d.name]
+				v.Distributions = nil
+				if !ok {
+					d.missing[component] = append(d.missing[component], k)
+					continue
+				}
+				v.ImagePath, v.ImageHash, v.ImageHashArm64 = img.ImagePath, img.ImageHash, img.ImageHashArm64
+			}
+		})
+	}
+}
+
+// check fails with FailedPrecondition if a selected version has no image of the distribution.
+func (d *distribution) check(m *pbVersion.VersionMatrix) error {
+	if d == nil {
+		return nil
+	}
+
+	for _, component := range slices.Sorted(maps.Keys(d.missing)) {
+		versions := componentVersions(m, component)
+		for _, k := range d.missing[component] {
+			if _, ok := versions[k]; ok {
+				return status.Errorf(codes.FailedPrecondition, "no %s image of %s %s", d.name, component, k)
+			}
+		}
+	}
+	return nil
+}
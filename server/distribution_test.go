@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+)
+
+var distributionTestSources = MemorySourceStore{
+	"components.json": []byte(`{
+  "pg-operator": {
+    "database": "postgresql",
+    "policy": "same-major-highest",
+    "components": [{"name": "pgbouncer", "selector": "pg_dep"}]
+  }
+}`),
+	"operator.2.0.0.pg-operator.json": []byte(`{
+  "versions": [
+    {
+      "operator": "2.0.0",
+      "product": "pg-operator",
+      "matrix": {
+        "postgresql": {
+          "16.4": {
+            "image_path": "percona/percona-postgresql:16.4",
+            "image_hash": "p164",
+            "status": "recommended",
+            "distributions": {
+              "percona": {"image_path": "percona/percona-postgresql:16.4", "image_hash": "p164"},
+              "community": {"image_path": "postgres:16.4", "image_hash": "c164"}
+            }
+          },
+          "17.2": {
+            "image_path": "percona/percona-postgresql:17.2",
+            "status": "recommended",
+            "distributions": {
+              "percona": {"image_path": "percona/percona-postgresql:17.2"}
+            }
+          }
+        },
+        "pgbouncer": {
+          "1.23.1": {
+            "image_path": "percona/percona-pgbouncer:1.23.1",
+            "status": "recommended",
+            "distributions": {
+              "percona": {"image_path": "percona/percona-pgbouncer:1.23.1"},
+              "community": {"image_path": "bitnami/pgbouncer:1.23.1"}
+            }
+          }
+        }
+      }
+    }
+  ]
+}`),
+	"operator.2.0.0.pg-operator.dep.json": []byte(`{
+  "pgbouncer": {
+    "1.23.1": {">=": [{"var": "productVersion"}, "16"]}
+  }
+}`),
+}
+
+func TestBackend_distribution(t *testing.T) {
+	t.Parallel()
+
+	b, err := New(distributionTestSources, fstest.MapFS{}, fstest.MapFS{})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		distribution   string
+		apply          string
+		wantPostgreSQL string
+		wantHash       string
+		wantPgBouncer  string
+		code           codes.Code
+	}{
+		"unknown": {
+			apply:          "16.4",
+			wantPostgreSQL: "percona/percona-postgresql:16.4",
+			wantHash:       "p164",
+			wantPgBouncer:  "percona/percona-pgbouncer:1.23.1",
+		},
+		"percona": {
+			distribution:   "percona",
+			apply:          "17.2",
+			wantPostgreSQL: "percona/percona-postgresql:17.2",
+			wantPgBouncer:  "percona/percona-pgbouncer:1.23.1",
+		},
+		"community": {
+			distribution:   "Community",
+			apply:          "16.4",
+			wantPostgreSQL: "postgres:16.4",
+			wantHash:       "c164",
+			wantPgBouncer:  "bitnami/pgbouncer:1.23.1",
+		},
+		"no community image": {
+			distribution: "community",
+			apply:        "17.2",
+			code:         codes.FailedPrecondition,
+		},
+		"invalid": {
+			distribution: "vanilla",
+			apply:        "16.4",
+			code:         codes.InvalidArgument,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resp, err := b.Apply(context.Background(), &pbVersion.ApplyRequest{
+				Product:         "pg-operator",
+				OperatorVersion: "2.0.0",
+				Apply:           tt.apply,
+				Distribution:    tt.distribution,
+			})
+			if tt.code != codes.OK {
+				assert.Equal(t, tt.code, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+
+			matrix := resp.Versions[0].Matrix
+			require.Len(t, matrix.Postgresql, 1)
+			require.Len(t, matrix.Pgbouncer, 1)
+			for _, v := range matrix.Postgresql {
+				assert.Equal(t, tt.wantPostgreSQL, v.ImagePath)
+				assert.Equal(t, tt.wantHash, v.ImageHash)
+			}
+			for _, v := range matrix.Pgbouncer {
+				assert.Equal(t, tt.wantPgBouncer, v.ImagePath)
+			}
+		})
+	}
+}
+
+func TestValidateDistributions(t *testing.T) {
+	t.Parallel()
+
+	sources := MemorySourceStore{
+		"operator.2.0.0.pg-operator.json": []byte(`{
+  "versions": [
+    {
+      "matrix": {
+        "postgresql": {
+          "16.4": {
+            "status": "recommended",
+            "distributions": {"vanilla": {"image_path": "postgres:16.4"}, "community": {}}
+          }
+        }
+      }
+    }
+  ]
+}`),
+	}
+
+	assert.Equal(t, []string{"matrix.postgresql.16.4.distributions.community", "matrix.postgresql.16.4.distributions.vanilla"},
+		validationIssueKeys(t, sources))
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+)
+
+// EmbargoOverrideHeader is the gRPC metadata key internal testers set to the embargo override
+// token to see versions before their publish_at.
+const EmbargoOverrideHeader = "x-embargo-override"
+
+// embargo hides versions before their publish_at. A nil embargo hides nothing.
+type embargo struct {
+	now time.Time
+}
+
+// embargo returns the embargo of the request or nil if the request overrides it with the configured token.
+// Requests with any other token fail, so testers notice they see only published versions. Without a
+// configured token the header is ignored, as proxies may add it.
+func (b *Backend) embargo(ctx context.Context) (*embargo, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	v := md.Get(EmbargoOverrideHeader)
+	if len(v) == 0 {
+		return &embargo{now: time.Now()}, nil
+	}
+	if b.embargoToken == "" {
+		ctxzap.Extract(ctx).Debug("embargo override header ignored, no token is configured")
+		return &embargo{now: time.Now()}, nil
+	}
+	if subtle.ConstantTimeCompare([]byte(v[0]), []byte(b.embargoToken)) != 1 {
+		return nil, status.Error(codes.Unauthenticated, "invalid embargo override token")
+	}
+	return nil, nil
+}
+
+// hides reports whether something published at publishAt is still embargoed.
+func (e *embargo) hides(publishAt *timestamppb.Timestamp) bool {
+	return e != nil && publishAt != nil && publishAt.AsTime().After(e.now)
+}
+
+// hidesSource reports whether all operator versions of the source are embargoed.
+func (e *embargo) hidesSource(vs *pbVersion.VersionResponse) bool {
+	if e == nil || len(vs.Versions) == 0 {
+		return false
+	}
+	return !slices.ContainsFunc(vs.Versions, func(ov *pbVersion.OperatorVersion) bool {
+		return !e.hides(ov.PublishAt)
+	})
+}
+
+// hidesComponent reports whether all versions of the matrix component are embargoed.
+func (e *embargo) hidesComponent(m *pbVersion.VersionMatrix, component string) bool {
+	versions := componentVersions(m, component)
+	if e == nil || len(versions) == 0 {
+		return false
+	}
+	for _, v := range versions {
+		if !e.hides(v.PublishAt) {
+			return false
+		}
+	}
+	return true
+}
+
+// filter removes embargoed operator versions and embargoed versions of their matrices.
+// The operator versions must be copies, they are modified.
+func (e *embargo) filter(versions []*pbVersion.OperatorVersion) []*pbVersion.OperatorVersion {
+	if e == nil {
+		return versions
+	}
+
+	versions = slices.DeleteFunc(versions, func(ov *pbVersion.OperatorVersion) bool {
+		return e.hides(ov.PublishAt)
+	})
+	for _, ov := range versions {
+		if ov.Matrix == nil {
+			continue
+		}
+		var components []string
+		rangeMatrix(ov.Matrix, func(component string, versions map[string]*pbVersion.Version) {
+			for _, v := range versions {
+				if e.hides(v.PublishAt) {
+					components = append(components, component)
+					return
+				}
+			}
+		})
+		for _, component := range components {
+			_ = filterComponent(ov.Matrix, component, func(versions map[string]*pbVersion.Version) error {
+				maps.DeleteFunc(versions, func(_ string, v *pbVersion.Version) bool {
+					return e.hides(v.PublishAt)
+				})
+				return nil
+			})
+		}
+	}
+	return versions
+}
+
+// filterVersions returns a copy of versions without the embargoed ones. The versions are shared, not copied.
+func (e *embargo) filterVersions(versions map[string]*pbVersion.Version) map[string]*pbVersion.Version {
+	if e == nil {
+		return versions
+	}
+	res := maps.Clone(versions)
+	maps.DeleteFunc(res, func(_ string, v *pbVersion.Version) bool {
+		return e.hides(v.PublishAt)
+	})
+	return res
+}
+
+// filterMetadata returns metadata versions without the embargoed ones. The versions are not modified.
+func filterMetadata[T interface{ GetPublishAt() *timestamppb.Timestamp }](e *embargo, versions []T) []T {
+	if e == nil || !slices.ContainsFunc(versions, func(v T) bool { return e.hides(v.GetPublishAt()) }) {
+		return versions
+	}
+	return slices.DeleteFunc(slices.Clone(versions), func(v T) bool {
+		return e.hides(v.GetPublishAt())
+	})
+}
@@ -0,0 +1,207 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+)
+
+func TestBackend_embargo(t *testing.T) {
+	t.Parallel()
+
+	overlay := Layer{
+		Sources: MemorySourceStore{
+			"operator.1.0.0.psmdb-operator.json": []byte(`{
+  "versions": [
+    {
+      "matrix": {
+        "mongod": {
+          "7.0.16-10": {
+            "image_path": "percona/percona-server-mongodb:7.0.16-10",
+            "status": "recommended",
+            "publish_at": "2100-01-01T00:00:00Z"
+          }
+        }
+      }
+    }
+  ]
+}`),
+			"operator.1.1.0.psmdb-operator.json": []byte(`{
+  "versions": [
+    {
+      "operator": "1.1.0",
+      "product": "psmdb-operator",
+      "publish_at": "2100-01-01T00:00:00Z",
+      "matrix": {
+        "mongod": {
+          "8.0.4-1": {"image_path": "percona/percona-server-mongodb:8.0.4-1", "status": "recommended"}
+        }
+      }
+    }
+  ]
+}`),
+			"operator.1.1.0.psmdb-operator.dep.json": []byte(`{}`),
+		},
+		Metadata: fstest.MapFS{
+			"everest/1.0.0.yaml": {Data: []byte("version: 1.0.0\n")},
+			"everest/1.1.0.yaml": {Data: []byte("version: 1.1.0\npublish_at: \"2100-01-01T00:00:00Z\"\n")},
+		},
+	}
+	b, err := NewLayered([]Layer{{Sources: testSources}, overlay}, WithEmbargoOverrideToken("secret"))
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		token            string
+		wantMongod       string
+		wantOperators    []string
+		wantMetadata     []string
+		wantOperatorCode codes.Code
+	}{
+		"embargoed": {
+			wantMongod:       "7.0.15-9",
+			wantOperators:    []string{"1.0.0"},
+			wantMetadata:     []string{"1.0.0"},
+			wantOperatorCode: codes.NotFound,
+		},
+		"override": {
+			token:         "secret",
+			wantMongod:    "7.0.16-10",
+			wantOperators: []string{"1.0.0", "1.1.0"},
+			wantMetadata:  []string{"1.0.0", "1.1.0"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			if tt.token != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(EmbargoOverrideHeader, tt.token))
+			}
+
+			resp, err := b.Apply(ctx, &pbVersion.ApplyRequest{
+				Product:         "psmdb-operator",
+				OperatorVersion: "1.0.0",
+				Apply:           "recommended",
+				DatabaseVersion: "7.0.14-8",
+			})
+			require.NoError(t, err)
+			assert.Contains(t, resp.Versions[0].Matrix.Mongod, tt.wantMongod)
+
+			product, err := b.Product(ctx, &pbVersion.ProductRequest{Product: "psmdb-operator"})
+			require.NoError(t, err)
+			var operators []string
+			for _, ov := range product.Versions {
+				operators = append(operators, ov.Operator)
+				if ov.Operator == "1.0.0" {
+					_, ok := ov.Matrix.Mongod["7.0.16-10"]
+					assert.Equal(t, tt.token != "", ok)
+				}
+			}
+			assert.Equal(t, tt.wantOperators, operators)
+
+			_, err = b.Operator(ctx, &pbVersion.OperatorRequest{Product: "psmdb-operator", OperatorVersion: "1.1.0"})
+			assert.Equal(t, tt.wantOperatorCode, status.Code(err))
+
+			latest, err := b.Operator(ctx, &pbVersion.OperatorRequest{Product: "psmdb-operator", OperatorVersion: "latest"})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOperators[len(tt.wantOperators)-1], latest.Versions[0].Operator)
+
+			meta, err := b.MetadataV2(ctx, &pbVersion.MetadataRequest{Product: "everest"})
+			require.NoError(t, err)
+			var versions []string
+			for _, v := range meta.Versions {
+				versions = append(versions, v.Version)
+			}
+			assert.Equal(t, tt.wantMetadata, versions)
+
+			metaV1, err := b.Metadata(ctx, &pbVersion.MetadataRequest{Product: "everest"})
+			require.NoError(t, err)
+			assert.Len(t, metaV1.Versions, len(tt.wantMetadata))
+		})
+	}
+
+	t.Run("invalid token", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(EmbargoOverrideHeader, "guess"))
+		_, err := b.Apply(ctx, &pbVersion.ApplyRequest{Product: "psmdb-operator", OperatorVersion: "1.0.0", Apply: "latest"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	})
+
+	t.Run("no token configured", func(t *testing.T) {
+		t.Parallel()
+
+		unconfigured, err := NewLayered([]Layer{{Sources: testSources}, overlay})
+		require.NoError(t, err)
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(EmbargoOverrideHeader, "secret"))
+		product, err := unconfigured.Product(ctx, &pbVersion.ProductRequest{Product: "psmdb-operator"})
+		require.NoError(t, err)
+		require.Len(t, product.Versions, 1)
+		assert.NotContains(t, product.Versions[0].Matrix.Mongod, "7.0.16-10")
+	})
+}
+
+func TestBackend_embargoOverlay(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		overlay          string
+		wantApplyCode    codes.Code
+		wantOperatorCode codes.Code
+	}{
+		"operator version": {
+			overlay:          `{"versions": [{"publish_at": "2100-01-01T00:00:00Z"}]}`,
+			wantApplyCode:    codes.NotFound,
+			wantOperatorCode: codes.NotFound,
+		},
+		"all database versions": {
+			overlay: `{
+  "versions": [
+    {
+      "matrix": {
+        "mongod": {
+          "7.0.14-8": {"publish_at": "2100-01-01T00:00:00Z"},
+          "7.0.15-9": {"publish_at": "2100-01-01T00:00:00Z"},
+          "8.0.4-1": {"publish_at": "2100-01-01T00:00:00Z"}
+        }
+      }
+    }
+  ]
+}`,
+			wantApplyCode:    codes.NotFound,
+			wantOperatorCode: codes.OK,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			overlay := Layer{Sources: MemorySourceStore{"operator.1.0.0.psmdb-operator.json": []byte(tt.overlay)}}
+			b, err := NewLayered([]Layer{{Sources: testSources}, overlay})
+			require.NoError(t, err)
+
+			for _, apply := range []string{"recommended", "latest", "7.0.15-9"} {
+				_, err = b.Apply(context.Background(), &pbVersion.ApplyRequest{
+					Product:         "psmdb-operator",
+					OperatorVersion: "1.0.0",
+					Apply:           apply,
+					DatabaseVersion: "7.0.14-8",
+				})
+				assert.Equal(t, tt.wantApplyCode, status.Code(err), apply)
+			}
+
+			_, err = b.Operator(context.Background(), &pbVersion.OperatorRequest{Product: "psmdb-operator", OperatorVersion: "1.0.0"})
+			assert.Equal(t, tt.wantOperatorCode, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"google.golang.org/grpc/metadata"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+)
+
+// ExplainHeader is the gRPC metadata key requesting the explanation of Apply
+// when it is not requested in the request.
+const ExplainHeader = "x-explain"
+
+// explainRequested reports whether the request or the X-Explain header asks for the explanation.
+func explainRequested(ctx context.Context, explain bool) bool {
+	if explain {
+		return true
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(ExplainHeader); len(v) > 0 {
+			b, _ := strconv.ParseBool(v[0])
+			return b
+		}
+	}
+	return false
+}
+
+// explanation records why Apply dropped versions of every component of a matrix.
+// A nil explanation records nothing.
+type explanation struct {
+	components map[string]*componentTrace
+}
+
+// componentTrace records versions of a component and the reasons they were dropped for.
+// Only the first reason of a version is kept, later filters only see what earlier ones kept.
+type componentTrace struct {
+	candidates []string
+	reasons    map[string]string
+}
+
+// newExplanation records versions of all components of the matrix as candidates.
+func newExplanation(m *pbVersion.VersionMatrix) *explanation {
+	e := &explanation{components: map[string]*componentTrace{}}
+	if m == nil {
+		return e
+	}
+	rangeMatrix(m, func(component string, versions map[string]*pbVersion.Version) {
+		if len(versions) == 0 {
+			return
+		}
+		e.components[component] = &componentTrace{
+			candidates: sortedKeysDesc(versions),
+			reasons:    map[string]string{},
+		}
+	})
+	return e
+}
+
+// component returns the trace of the component or nil if nothing is recorded for it.
+func (e *explanation) component(name string) *componentTrace {
+	if e == nil {
+		return nil
+	}
+	return e.components[name]
+}
+
+// drop records the reason version v is dropped for unless it already has one.
+func (t *componentTrace) drop(v string, format string, args ...interface{}) {
+	if t == nil {
+		return
+	}
+	if _, ok := t.reasons[v]; !ok {
+		t.reasons[v] = fmt.Sprintf(format, args...)
+	}
+}
+
+// override records the reason version v is dropped for, replacing a recorded one.
+func (t *componentTrace) override(v string, format string, args ...interface{}) {
+	if t == nil {
+		return
+	}
+	t.reasons[v] = fmt.Sprintf(format, args...)
+}
+
+// result explains the selection of versions left in the matrix. Dropped versions
+// without a recorded reason are explained by the selected version they are lower than.
+func (e *explanation) result(m *pbVersion.VersionMatrix) []*pbVersion.ComponentExplanation {
+	if e == nil {
+		return nil
+	}
+
+	res := make([]*pbVersion.ComponentExplanation, 0, len(e.components))
+	for _, name := range slices.Sorted(maps.Keys(e.components)) {
+		t := e.components[name]
+		var versions map[string]*pbVersion.Version
+		if m != nil {
+			versions = componentVersions(m, name)
+		}
+
+		ce := &pbVersion.ComponentExplanation{Component: name, Selected: sortedKeysDesc(versions)}
+		for _, v := range t.candidates {
+			c := &pbVersion.CandidateExplanation{Version: v}
+			if _, ok := versions[v]; ok {
+				c.Selected = true
+			} else if reason, ok := t.reasons[v]; ok {
+				c.Reason = reason
+			} else {
+				c.Reason = notSelectedReason(v, ce.Selected)
+			}
+			ce.Candidates = append(ce.Candidates, c)
+		}
+		res = append(res, ce)
+	}
+	return res
+}
+
+// notSelectedReason explains why v was dropped by the lowest selected version higher than it.
+func notSelectedReason(v string, selected []string) string {
+	sv, err := semver.NewVersion(v)
+	if err != nil {
+		return "not selected"
+	}
+	reason := "not selected"
+	for _, s := range selected {
+		if ss, err := semver.NewVersion(s); err == nil && ss.GreaterThan(sv) {
+			reason = fmt.Sprintf("lower than selected version %s", s)
+		}
+	}
+	return reason
+}
+
+// sortedKeysDesc returns versions sorted from the highest one. Invalid versions are sorted last by name.
+func sortedKeysDesc(versions map[string]*pbVersion.Version) []string {
+	keys := slices.Collect(maps.Keys(versions))
+	slices.SortFunc(keys, func(a, b string) int {
+		av, aErr := semver.NewVersion(a)
+		bv, bErr := semver.NewVersion(b)
+		switch {
+		case aErr != nil || bErr != nil:
+			if aErr == nil {
+				return -1
+			}
+			if bErr == nil {
+				return 1
+			}
+			return strings.Compare(a, b)
+		case av.Equal(bv):
+			return strings.Compare(a, b)
+		}
+		return bv.Compare(av)
+	})
+	return keys
+}
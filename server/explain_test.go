@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+)
+
+func TestBackend_explain(t *testing.T) {
+	t.Parallel()
+
+	b, err := New(testSources, fstest.MapFS{}, fstest.MapFS{})
+	require.NoError(t, err)
+
+	want := []*pbVersion.ComponentExplanation{
+		{
+			Component: "backup",
+			Candidates: []*pbVersion.CandidateExplanation{
+				{Version: "2.8.0", Reason: "dependency rule is false for product version 7.0.15-9"},
+				{Version: "2.7.0", Selected: true},
+			},
+			Selected: []string{"2.7.0"},
+		},
+		{
+			Component: "mongod",
+			Candidates: []*pbVersion.CandidateExplanation{
+				{Version: "8.0.4-1", Reason: "status is available, not recommended"},
+				{Version: "7.0.15-9", Selected: true},
+				{Version: "7.0.14-8", Reason: "status is available, not recommended"},
+			},
+			Selected: []string{"7.0.15-9"},
+		},
+		{
+			Component: "operator",
+			Candidates: []*pbVersion.CandidateExplanation{
+				{Version: "1.0.0", Selected: true},
+			},
+			Selected: []string{"1.0.0"},
+		},
+		{
+			Component: "pmm",
+			Candidates: []*pbVersion.CandidateExplanation{
+				{Version: "3.1.0", Selected: true},
+				{Version: "2.44.0", Selected: true},
+			},
+			Selected: []string{"3.1.0", "2.44.0"},
+		},
+	}
+
+	tests := map[string]struct {
+		explain bool
+		header  string
+		want    []*pbVersion.ComponentExplanation
+	}{
+		"not requested": {},
+		"request":       {explain: true, want: want},
+		"header":        {header: "true", want: want},
+		"header false":  {header: "false"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			if tt.header != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(ExplainHeader, tt.header))
+			}
+			resp, err := b.Apply(ctx, &pbVersion.ApplyRequest{
+				Product:         "psmdb-operator",
+				OperatorVersion: "1.0.0",
+				Apply:           "recommended",
+				DatabaseVersion: "7.0.14-8",
+				Explain:         tt.explain,
+			})
+			require.NoError(t, err)
+			assert.Empty(t, cmp.Diff(tt.want, resp.Explanation, protocmp.Transform()))
+		})
+	}
+}
+
+func TestBackend_explainReasons(t *testing.T) {
+	t.Parallel()
+
+	overlay := Layer{Sources: MemorySourceStore{
+		"operator.1.0.0.psmdb-operator.json": []byte(`{
+  "versions": [
+    {
+      "matrix": {
+        "mongod": {
+          "7.0.16-10": {"status": "disabled"},
+          "8.0.4-1": {"supported_kube_versions": ">= 1.34"}
+        }
+      }
+    }
+  ]
+}`),
+	}}
+	b, err := NewLayered([]Layer{{Sources: testSources}, overlay})
+	require.NoError(t, err)
+
+	resp, err := b.Apply(context.Background(), &pbVersion.ApplyRequest{
+		Product:         "psmdb-operator",
+		OperatorVersion: "1.0.0",
+		Apply:           "latest",
+		DatabaseVersion: "7.0.14-8",
+		KubeVersion:     "1.31.2",
+		Explain:         true,
+	})
+	require.NoError(t, err)
+
+	reasons := map[string]string{}
+	for _, c := range resp.Explanation {
+		if c.Component != "mongod" {
+			continue
+		}
+		for _, v := range c.Candidates {
+			reasons[v.Version] = v.Reason
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"8.0.4-1":   "Kubernetes 1.31.2 does not match >= 1.34",
+		"7.0.16-10": "status is disabled",
+		"7.0.15-9":  "",
+		"7.0.14-8":  "lower than selected version 7.0.15-9",
+	}, reasons)
+}
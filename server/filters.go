@@ -1,9 +1,6 @@
 package server
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
 	"regexp"
 	"slices"
 	"sort"
@@ -11,7 +8,7 @@ import (
 
 	"github.com/Masterminds/semver"
 	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
-	"github.com/diegoholiveira/jsonlogic"
+	"github.com/diegoholiveira/jsonlogic/v3"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -25,200 +22,126 @@ const (
 
 var majorMinorRegexp = regexp.MustCompile(`^(\d+\.)?(\d+)`)
 
-func psmdbFilter(versions map[string]*pbVersion.Version, apply string, current string) error {
-	if len(versions) == 0 {
-		return status.Error(codes.Internal, "no versions to filter")
-	}
-
-	keys := make([]string, 0, len(versions))
-	for k, v := range versions {
-		if strings.ToLower(apply) == recommended && v.Status != pbVersion.Status_recommended {
-			continue
-		}
-
-		keys = append(keys, k)
-	}
+// rangeAndRegexp matches spaces separating constraints of a range, e.g. >=7.0 <8.0,
+// which the semver library expects to be separated by commas.
+var rangeAndRegexp = regexp.MustCompile(`([^\s,|])\s+([<>=!~^])`)
 
-	sorted, err := sortedVersionsDesc(keys, true)
-	if err != nil {
-		return status.Errorf(codes.Internal, "failed to sort versions: %v", err)
-	}
-
-	if (strings.ToLower(apply) == recommended || strings.ToLower(apply) == latest) && current == "" {
-		return deleteOtherBut(sorted[0].String(), versions)
-	}
-
-	desired := apply //assume version number
-	if strings.ToLower(apply) == recommended || strings.ToLower(apply) == latest {
-		desired = current
-
-		c, err := semver.NewVersion(current)
-		if err != nil {
-			return status.Errorf(codes.InvalidArgument, "invalid current version: %s", current)
-		}
-
-		for _, s := range sorted {
-			if s.Equal(c) || s.LessThan(c) {
-				break
-			}
+// versionRange is a range of database versions requested by the apply option, e.g. ~8.0.36-latest.
+type versionRange struct {
+	expr        string
+	constraints *semver.Constraints
+	// apply is recommended or latest.
+	apply string
+}
 
-			if versions[s.String()].Status != pbVersion.Status_disabled && c.Major() == s.Major() && c.Minor() == s.Minor() {
-				desired = s.String()
-				break
-			}
+// parseVersionRange parses a semver range, e.g. ~8.0.36, ^16 or >=7.0 <8.0, optionally followed
+// by -recommended or -latest. Ranges select recommended versions by default.
+// Exact versions are not ranges.
+func parseVersionRange(apply string) (versionRange, bool) {
+	r := versionRange{expr: apply, apply: recommended}
+	for _, a := range []string{recommended, latest} {
+		if expr, ok := strings.CutSuffix(apply, "-"+a); ok {
+			r.expr, r.apply = expr, a
+			break
 		}
 	}
-
-	err = deleteOtherBut(desired, versions)
-	if err != nil {
-		return err
+	if _, err := semver.NewVersion(r.expr); err == nil {
+		return versionRange{}, false
 	}
-	if len(versions) == 0 {
-		return status.Errorf(codes.NotFound, "version %s does not exist", desired)
+	c, err := parseRange(r.expr)
+	if err != nil {
+		return versionRange{}, false
 	}
+	r.constraints = c
+	return r, true
+}
 
-	return nil
+// parseRange parses a semver range allowing spaces between constraints, e.g. >=7.0 <8.0.
+func parseRange(expr string) (*semver.Constraints, error) {
+	return semver.NewConstraint(rangeAndRegexp.ReplaceAllString(expr, "$1,$2"))
 }
 
-func pxcFilter(versions map[string]*pbVersion.Version, apply string, current string) error {
-	if len(versions) == 0 {
-		return status.Error(codes.Internal, "no versions to filter")
-	}
+// check reports whether v is in the range. Suffixes of database versions, e.g. -28 of 8.0.36-28,
+// are build numbers rather than prereleases, so only major.minor.patch is checked.
+func (r versionRange) check(v *semver.Version) bool {
+	return r.constraints.Check(releaseVersion(v))
+}
 
+// filter deletes all versions but the highest non-disabled one in the range,
+// only recommended versions are considered for recommended.
+func (r versionRange) filter(versions map[string]*pbVersion.Version, t *componentTrace) error {
 	keys := make([]string, 0, len(versions))
 	for k, v := range versions {
-		if strings.ToLower(apply) == recommended && v.Status != pbVersion.Status_recommended {
-			continue
+		sv, err := semver.NewVersion(k)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to parse version: %s", k)
+		}
+		switch {
+		case !r.check(sv):
+			t.drop(k, "not in range %s", r.expr)
+		case v.Status == pbVersion.Status_disabled:
+			t.drop(k, "status is disabled")
+		case r.apply == recommended && v.Status != pbVersion.Status_recommended:
+			t.drop(k, "status is %s, not recommended", v.Status)
+		default:
+			keys = append(keys, k)
 		}
-
-		keys = append(keys, k)
 	}
 
 	sorted, err := sortedVersionsDesc(keys, true)
 	if err != nil {
 		return status.Errorf(codes.Internal, "failed to sort versions: %v", err)
 	}
-
-	if (strings.ToLower(apply) == recommended || strings.ToLower(apply) == latest) && current == "" {
-		return deleteOtherBut(sorted[0].String(), versions)
-	}
-
-	desired := apply //assume version number
-	if strings.ToLower(apply) == recommended || strings.ToLower(apply) == latest {
-		desired = current
-
-		c, err := semver.NewVersion(current)
-		if err != nil {
-			return status.Errorf(codes.InvalidArgument, "invalid current version: %s", current)
-		}
-
-		for _, s := range sorted {
-			if s.Equal(c) || (s.Major() == c.Major() && s.Minor() == c.Minor() && s.Patch() < c.Patch()) {
-				break
-			}
-
-			if versions[s.String()].Status != pbVersion.Status_disabled &&
-				c.Major() == s.Major() && c.Minor() == s.Minor() {
-				desired = s.String()
-				if strings.ToLower(apply) == latest {
-					break
-				}
-			}
-		}
+	if len(sorted) == 0 {
+		return status.Errorf(codes.NotFound, "no %s version in range %s", r.apply, r.expr)
 	}
+	return keepVersion(sorted[0].Original(), versions)
+}
 
-	err = deleteOtherBut(desired, versions)
-	if err != nil {
-		return err
-	}
-	if len(versions) == 0 {
-		return status.Errorf(codes.NotFound, "version %s does not exist", desired)
-	}
+// selectionPolicy selects the database version for an apply option and the current version.
+// Apply set to a version number selects that version, set to a range the highest version
+// in the range regardless of the current version. latest and recommended select
+// the highest non-disabled version (recommended only considers recommended ones)
+// if there is no current version, otherwise a newer one on the line of the current version.
+type selectionPolicy struct {
+	// sameMajor allows moving to newer minor versions of the current major,
+	// otherwise only versions of the current major.minor are considered.
+	sameMajor bool
+	// nextRecommended makes recommended select the lowest recommended version newer than
+	// the current one instead of the highest, so clusters go through every recommended version.
+	nextRecommended bool
+	// majorMinorCurrent compares only major.minor of the current version, e.g. 16.4 of 16.4-1.
+	majorMinorCurrent bool
+}
 
-	return nil
+// selectionPolicies are the policies products can declare in the component registry.
+var selectionPolicies = map[string]selectionPolicy{
+	"same-minor-highest":          {},
+	"same-minor-next-recommended": {nextRecommended: true},
+	"same-major-highest":          {sameMajor: true, majorMinorCurrent: true},
 }
 
-func pgFilter(versions map[string]*pbVersion.Version, apply string, current string) error {
+// filter deletes all versions but the selected one and records the reasons for dropping them in t.
+func (p selectionPolicy) filter(versions map[string]*pbVersion.Version, apply string, current string, t *componentTrace) error {
 	if len(versions) == 0 {
 		return status.Error(codes.Internal, "no versions to filter")
 	}
 
-	keys := make([]string, 0, len(versions))
-	for k, v := range versions {
-		if strings.ToLower(apply) == recommended && v.Status != pbVersion.Status_recommended {
-			continue
-		}
-
-		keys = append(keys, k)
-	}
-
-	sorted, err := sortedVersionsDesc(keys, true)
-	if err != nil {
-		return status.Errorf(codes.Internal, "failed to sort versions: %v", err)
-	}
-
 	apply = strings.ToLower(apply)
-
-	if (apply == recommended || apply == latest) && current == "" {
-		return deleteOtherBut(sorted[0].String(), versions)
+	if r, ok := parseVersionRange(apply); ok {
+		return r.filter(versions, t)
 	}
-
-	currentVer := majorMinorRegexp.FindString(current)
-
-	desired := apply //assume version number
-	if apply == recommended || apply == latest {
-		desired = currentVer
-
-		c, err := semver.NewVersion(currentVer)
-		if err != nil {
-			return status.Errorf(codes.InvalidArgument, "invalid current version: %s", currentVer)
-		}
-
-		for _, s := range sorted {
-			if s.Equal(c) || s.LessThan(c) {
-				break
-			}
-
-			str := s.String()
-			v, ok := versions[str]
-			if !ok {
-				// let's try to find version witout Patch if Patch is equal to zero
-				if strings.HasSuffix(str, ".0") {
-					v, ok = versions[strings.TrimSuffix(str, ".0")]
-					if !ok {
-						return status.Errorf(codes.Internal, "failed to filter version %s", str)
-					}
-				} else {
-					return status.Errorf(codes.Internal, "failed to filter version %s", str)
-				}
-			}
-
-			if v.Status != pbVersion.Status_disabled && c.Major() == s.Major() {
-				desired = s.String()
-				break
-			}
+	if apply != recommended && apply != latest {
+		for k := range versions {
+			t.drop(k, "apply requests version %s", apply)
 		}
-	}
-
-	err = deleteOtherBut(desired, versions)
-	if err != nil {
-		return err
-	}
-	if len(versions) == 0 {
-		return status.Errorf(codes.NotFound, "version %s does not exist", desired)
-	}
-
-	return nil
-}
-
-func psFilter(versions map[string]*pbVersion.Version, apply string, current string) error {
-	if len(versions) == 0 {
-		return status.Error(codes.Internal, "no versions to filter")
+		return keepVersion(apply, versions) // assume version number
 	}
 
 	keys := make([]string, 0, len(versions))
 	for k, v := range versions {
-		if strings.ToLower(apply) == recommended && v.Status != pbVersion.Status_recommended {
+		if apply == recommended && v.Status != pbVersion.Status_recommended {
+			t.drop(k, "status is %s, not recommended", v.Status)
 			continue
 		}
 
@@ -230,42 +153,61 @@ func psFilter(versions map[string]*pbVersion.Version, apply string, current stri
 		return status.Errorf(codes.Internal, "failed to sort versions: %v", err)
 	}
 
-	apply = strings.ToLower(apply)
-
-	if (apply == recommended || apply == latest) && current == "" {
-		return deleteOtherBut(sorted[0].String(), versions)
+	if current == "" {
+		if len(sorted) == 0 {
+			return status.Errorf(codes.NotFound, "no %s version", apply)
+		}
+		return keepVersion(sorted[0].Original(), versions)
 	}
 
-	desired := apply //assume version number
-	if apply == recommended || apply == latest {
-		desired = current
-
-		c, err := semver.NewVersion(current)
-		if err != nil {
-			return status.Errorf(codes.InvalidArgument, "invalid current version: %s", current)
-		}
+	if p.majorMinorCurrent {
+		current = majorMinorRegexp.FindString(current)
+	}
+	c, err := semver.NewVersion(current)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid current version: %s", current)
+	}
 
-		for _, s := range sorted {
-			if s.Equal(c) || s.LessThan(c) {
-				break
+	desired := current
+	for i, s := range sorted {
+		if !s.GreaterThan(c) {
+			for _, o := range sorted[i:] {
+				t.drop(o.Original(), "not newer than current version %s", current)
 			}
+			break
+		}
+		switch {
+		case versions[s.Original()].Status == pbVersion.Status_disabled:
+			t.drop(s.Original(), "status is disabled")
+			continue
+		case s.Major() != c.Major():
+			t.drop(s.Original(), "major version differs from current version %s", current)
+			continue
+		case !p.sameMajor && s.Minor() != c.Minor():
+			t.drop(s.Original(), "minor version differs from current version %s", current)
+			continue
+		}
 
-			if versions[s.String()].Status != pbVersion.Status_disabled &&
-				c.Major() == s.Major() && c.Minor() == s.Minor() {
-				desired = s.String()
-				if strings.ToLower(apply) == latest {
-					break
-				}
-			}
+		if desired != current {
+			t.drop(desired, "the lowest recommended version newer than current version %s is selected", current)
+		}
+		desired = s.Original()
+		if apply == latest || !p.nextRecommended {
+			break
 		}
 	}
 
-	err = deleteOtherBut(desired, versions)
+	return keepVersion(desired, versions)
+}
+
+// keepVersion deletes all versions but v and fails if v does not exist.
+func keepVersion(v string, versions map[string]*pbVersion.Version) error {
+	err := deleteOtherBut(v, versions)
 	if err != nil {
 		return err
 	}
 	if len(versions) == 0 {
-		return status.Errorf(codes.NotFound, "version %s does not exist", desired)
+		return status.Errorf(codes.NotFound, "version %s does not exist", v)
 	}
 
 	return nil
@@ -331,88 +273,37 @@ func defaultFilter(versions map[string]*pbVersion.Version, apply string, preVerI
 	return deleteOtherBut(apply, versions)
 }
 
-func pgDepFilter(versions map[string]interface{}, productVersion string) (string, error) {
-	if len(versions) == 0 {
-		return "", nil
-	}
-
-	keys := make([]string, 0, len(versions))
-	for k := range versions {
-		keys = append(keys, k)
-	}
-
-	sorted, err := sortedVersionsDesc(keys, true)
-	if err != nil {
-		return "", status.Errorf(codes.Internal, "failed to sort versions: %v", err)
-	}
-
-	desired := sorted[0].String()
-	for _, s := range sorted {
-		versStr := s.String()
-		if strings.HasSuffix(versStr, ".0") {
-			versStr = strings.TrimSuffix(versStr, ".0")
-
-		}
-		b, err := json.Marshal(versions[versStr])
-		if err != nil {
-			return "", status.Errorf(codes.Internal, "failed to marshal deps logic: %v", err)
-		}
-
-		logic := bytes.NewReader(b)
-		data := strings.NewReader(fmt.Sprintf(`{  "productVersion" : "%s" }`, productVersion))
-
-		var result bytes.Buffer
-		err = jsonlogic.Apply(logic, data, &result)
-		if err != nil {
-			return "", status.Errorf(codes.Internal, "failed to apply logic: %v", err)
+// depRuleTrue reports whether the dependency rule is true for the product version.
+// jsonlogic panics on operands operators don't expect, the panics are returned as errors.
+func depRuleTrue(rule interface{}, productVersion string) (_ bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = status.Errorf(codes.Internal, "failed to apply logic: %v", r)
 		}
+	}()
 
-		if strings.TrimSuffix(result.String(), "\n") == "true" {
-			desired = s.String()
-			break
-		}
+	res, err := jsonlogic.ApplyInterface(rule, map[string]interface{}{"productVersion": productVersion})
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "failed to apply logic: %v", err)
 	}
-
-	return desired, nil
+	return res == true, nil
 }
 
-func depFilter(versions map[string]interface{}, productVersion string) (string, error) {
-	if len(versions) == 0 {
-		return "", nil
+// explainDeps records the reasons versions without a dependency rule true for the product version are dropped for.
+func explainDeps(t *componentTrace, versions map[string]*pbVersion.Version, rules *depRules, productVersion string) error {
+	if t == nil {
+		return nil
 	}
-
-	keys := make([]string, 0, len(versions))
 	for k := range versions {
-		keys = append(keys, k)
-	}
-
-	sorted, err := sortedVersionsDesc(keys, true)
-	if err != nil {
-		return "", status.Errorf(codes.Internal, "failed to sort versions: %v", err)
-	}
-
-	desired := sorted[0].String()
-	for _, s := range sorted {
-		b, err := json.Marshal(versions[s.String()])
-		if err != nil {
-			return "", status.Errorf(codes.Internal, "failed to marshal deps logic: %v", err)
-		}
-		logic := bytes.NewReader(b)
-		data := strings.NewReader(fmt.Sprintf(`{  "productVersion" : "%s" }`, productVersion))
-
-		var result bytes.Buffer
-		err = jsonlogic.Apply(logic, data, &result)
+		reason, err := rules.reason(k, productVersion)
 		if err != nil {
-			return "", status.Errorf(codes.Internal, "failed to apply logic: %v", err)
+			return err
 		}
-
-		if strings.TrimSuffix(result.String(), "\n") == "true" {
-			desired = s.String()
-			break
+		if reason != "" {
+			t.drop(k, "%s", reason)
 		}
 	}
-
-	return desired, nil
+	return nil
 }
 
 func deleteOtherBut(v string, versions map[string]*pbVersion.Version) error {
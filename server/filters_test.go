@@ -5,6 +5,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
 )
@@ -88,3 +90,192 @@ func TestPMMFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestSelectionPolicies(t *testing.T) {
+	t.Parallel()
+
+	rec, avail, dis := pbVersion.Status_recommended, pbVersion.Status_available, pbVersion.Status_disabled
+	products := map[string]map[string]pbVersion.Status{
+		"mongod": {
+			"6.0.19-16": rec,
+			"7.0.12-7":  rec,
+			"7.0.14-8":  rec,
+			"7.0.15-9":  avail,
+			"7.0.16-10": dis,
+			"8.0.4-1":   rec,
+		},
+		"pxc": {
+			"5.7.44-31.65": rec,
+			"8.0.36-28.1":  rec,
+			"8.0.37-29.1":  rec,
+			"8.0.39-30.1":  rec,
+			"8.0.40-31.1":  avail,
+			"8.4.2-2.1":    rec,
+			"8.4.3-3.1":    dis,
+		},
+		"postgresql": {
+			"15.8": rec,
+			"16.3": rec,
+			"16.4": rec,
+			"16.6": rec,
+			"16.8": avail,
+			"16.9": dis,
+			"17.2": rec,
+		},
+		"mysql": {
+			"8.0.36-28": rec,
+			"8.0.39-30": rec,
+			"8.0.40-31": rec,
+			"8.0.41-32": avail,
+			"8.4.3-3":   rec,
+			"8.4.4-4":   dis,
+		},
+	}
+
+	type testCase struct {
+		product string
+		apply   string
+		current string
+		want    string
+	}
+	// every policy selects the same versions without the current version and for exact versions
+	common := []testCase{
+		{product: "mongod", apply: "latest", want: "8.0.4-1"},
+		{product: "mongod", apply: "Recommended", want: "8.0.4-1"},
+		{product: "mongod", apply: "7.0.15-9", current: "7.0.12-7", want: "7.0.15-9"},
+		{product: "pxc", apply: "latest", want: "8.4.3-3.1"},
+		{product: "pxc", apply: "recommended", want: "8.4.2-2.1"},
+		{product: "pxc", apply: "8.0.37-29.1", want: "8.0.37-29.1"},
+		{product: "postgresql", apply: "latest", want: "17.2"},
+		{product: "postgresql", apply: "recommended", want: "17.2"},
+		{product: "postgresql", apply: "16.3", want: "16.3"},
+		{product: "mysql", apply: "latest", want: "8.4.4-4"},
+		{product: "mysql", apply: "recommended", want: "8.4.3-3"},
+		{product: "mysql", apply: "8.0.39-30", want: "8.0.39-30"},
+		// ranges ignore the current version
+		{product: "mongod", apply: "~7.0.12", current: "6.0.19-16", want: "7.0.14-8"},
+		{product: "mongod", apply: "~7.0.12-latest", want: "7.0.15-9"},
+		{product: "mongod", apply: ">=6.0 <8.0", want: "7.0.14-8"},
+		{product: "pxc", apply: "~8.0.36", current: "8.0.36-28.1", want: "8.0.39-30.1"},
+		{product: "pxc", apply: "^8.0.37-latest", want: "8.4.2-2.1"},
+		{product: "postgresql", apply: "^16", current: "16.3", want: "16.6"},
+		{product: "postgresql", apply: "^16-Latest", want: "16.8"},
+		{product: "mysql", apply: "8.0.x", want: "8.0.40-31"},
+		{product: "mysql", apply: ">=8.0.37, <8.4-latest", want: "8.0.41-32"},
+	}
+	tests := map[string][]testCase{
+		"same-minor-highest": {
+			{product: "mongod", apply: "latest", current: "7.0.12-7", want: "7.0.15-9"},
+			{product: "mongod", apply: "recommended", current: "7.0.12-7", want: "7.0.14-8"},
+			{product: "mongod", apply: "latest", current: "8.0.4-1", want: "8.0.4-1"},
+			{product: "pxc", apply: "latest", current: "8.0.36-28.1", want: "8.0.40-31.1"},
+			{product: "pxc", apply: "recommended", current: "8.0.36-28.1", want: "8.0.39-30.1"},
+			{product: "pxc", apply: "latest", current: "8.4.2-2.1", want: "8.4.2-2.1"},
+			// PostgreSQL versions have no patch, so the current minor has a single version
+			{product: "postgresql", apply: "latest", current: "16.3", want: "16.3"},
+			{product: "postgresql", apply: "recommended", current: "16.3", want: "16.3"},
+			{product: "postgresql", apply: "latest", current: "15.8", want: "15.8"},
+			{product: "mysql", apply: "latest", current: "8.0.36-28", want: "8.0.41-32"},
+			{product: "mysql", apply: "recommended", current: "8.0.36-28", want: "8.0.40-31"},
+			{product: "mysql", apply: "latest", current: "8.4.3-3", want: "8.4.3-3"},
+		},
+		"same-minor-next-recommended": {
+			{product: "mongod", apply: "latest", current: "7.0.12-7", want: "7.0.15-9"},
+			{product: "mongod", apply: "recommended", current: "7.0.12-7", want: "7.0.14-8"},
+			{product: "mongod", apply: "recommended", current: "7.0.14-8", want: "7.0.14-8"},
+			{product: "pxc", apply: "latest", current: "8.0.36-28.1", want: "8.0.40-31.1"},
+			{product: "pxc", apply: "recommended", current: "8.0.36-28.1", want: "8.0.37-29.1"},
+			{product: "pxc", apply: "recommended", current: "8.0.39-30.1", want: "8.0.39-30.1"},
+			{product: "postgresql", apply: "latest", current: "16.3", want: "16.3"},
+			{product: "postgresql", apply: "recommended", current: "16.3", want: "16.3"},
+			{product: "postgresql", apply: "recommended", current: "16.6", want: "16.6"},
+			{product: "mysql", apply: "latest", current: "8.0.36-28", want: "8.0.41-32"},
+			{product: "mysql", apply: "recommended", current: "8.0.36-28", want: "8.0.39-30"},
+			{product: "mysql", apply: "recommended", current: "8.0.39-30", want: "8.0.40-31"},
+		},
+		"same-major-highest": {
+			{product: "mongod", apply: "latest", current: "6.0.19-16", want: "6.0.19-16"},
+			{product: "mongod", apply: "recommended", current: "7.0.12-7", want: "7.0.14-8"},
+			{product: "mongod", apply: "latest", current: "7.0.15-9", want: "7.0.15-9"},
+			{product: "pxc", apply: "latest", current: "8.0.36-28.1", want: "8.4.2-2.1"},
+			{product: "pxc", apply: "recommended", current: "8.0.36-28.1", want: "8.4.2-2.1"},
+			{product: "pxc", apply: "latest", current: "5.7.44-31.65", want: "5.7.44-31.65"},
+			{product: "postgresql", apply: "latest", current: "16.3-1", want: "16.8"},
+			{product: "postgresql", apply: "recommended", current: "16.3", want: "16.6"},
+			{product: "postgresql", apply: "latest", current: "15.8", want: "15.8"},
+			{product: "mysql", apply: "latest", current: "8.0.36-28", want: "8.4.3-3"},
+			{product: "mysql", apply: "recommended", current: "8.0.36-28", want: "8.4.3-3"},
+			{product: "mysql", apply: "latest", current: "8.4.3-3", want: "8.4.3-3"},
+		},
+	}
+	for policy, cases := range tests {
+		p, ok := selectionPolicies[policy]
+		require.True(t, ok, policy)
+
+		for _, tt := range append(cases, common...) {
+			t.Run(policy+"/"+tt.product+"/"+tt.apply+"/"+tt.current, func(t *testing.T) {
+				t.Parallel()
+
+				versions := make(map[string]*pbVersion.Version, len(products[tt.product]))
+				for k, s := range products[tt.product] {
+					versions[k] = &pbVersion.Version{Status: s}
+				}
+				err := p.filter(versions, tt.apply, tt.current, nil)
+				require.NoError(t, err)
+				assert.Len(t, versions, 1)
+				assert.Contains(t, versions, tt.want)
+			})
+		}
+	}
+	assert.Len(t, tests, len(selectionPolicies))
+}
+
+func TestSelectionPolicy_filterErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		versions map[string]*pbVersion.Version
+		apply    string
+		current  string
+		code     codes.Code
+	}{
+		"no versions": {
+			apply: "latest",
+			code:  codes.Internal,
+		},
+		"no recommended version": {
+			versions: map[string]*pbVersion.Version{"8.0.4-1": {Status: pbVersion.Status_available}},
+			apply:    "recommended",
+			code:     codes.NotFound,
+		},
+		"invalid current version": {
+			versions: map[string]*pbVersion.Version{"8.0.4-1": {Status: pbVersion.Status_recommended}},
+			apply:    "latest",
+			current:  "eight",
+			code:     codes.InvalidArgument,
+		},
+		"no version in range": {
+			versions: map[string]*pbVersion.Version{"8.0.4-1": {Status: pbVersion.Status_recommended}},
+			apply:    "^7.0",
+			code:     codes.NotFound,
+		},
+		"no recommended version in range": {
+			versions: map[string]*pbVersion.Version{"8.0.4-1": {Status: pbVersion.Status_available}},
+			apply:    "~8.0.4",
+			code:     codes.NotFound,
+		},
+		"unknown version": {
+			versions: map[string]*pbVersion.Version{"8.0.4-1": {Status: pbVersion.Status_recommended}},
+			apply:    "8.0.5-2",
+			code:     codes.NotFound,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := selectionPolicies["same-minor-highest"].filter(tt.versions, tt.apply, tt.current, nil)
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"slices"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Layer is a single root of version service sources.
+// Any of its fields may be nil if the layer does not provide such sources.
+type Layer struct {
+	// Name identifies the layer in validation reports, e.g. the directory it is read from.
+	Name         string
+	Sources      SourceStore
+	Metadata     fs.FS
+	ReleaseNotes fs.FS
+}
+
+// sourceOrigin records the files of the layers a merged source is read from, so issues found only
+// in the merged source are reported against the file they come from.
+type sourceOrigin struct {
+	// file is the first file of the source.
+	file string
+	// defined holds the first file defining each operator version and matrix version keyed by
+	// validation key, e.g. versions.0 or matrix.mongod.7.0.15-9.
+	defined map[string]string
+	// withdrawn holds the last file withdrawing each matrix version keyed by validation key.
+	withdrawn map[string]string
+}
+
+func newSourceOrigin(file string) *sourceOrigin {
+	return &sourceOrigin{file: file, defined: map[string]string{}, withdrawn: map[string]string{}}
+}
+
+// record records the entries of a file of the source. Files must be recorded in layer order.
+func (o *sourceOrigin) record(file string, vs *pbVersion.VersionResponse) {
+	define := func(key string) {
+		if _, ok := o.defined[key]; !ok {
+			o.defined[key] = file
+		}
+	}
+	for i, ov := range vs.Versions {
+		define(fmt.Sprintf("versions.%d", i))
+		if ov.Matrix == nil {
+			continue
+		}
+		rangeMatrix(ov.Matrix, func(component string, versions map[string]*pbVersion.Version) {
+			for k, v := range versions {
+				key := fmt.Sprintf("matrix.%s.%s", component, k)
+				define(key)
+				if v.Withdrawn != nil {
+					o.withdrawn[key] = file
+				}
+			}
+		})
+	}
+}
+
+// mergeVersionResponse merges overlay into base. Operator versions are matched by position,
+// components of the version matrix are merged per version: fields set in overlay
+// override the ones in base and versions missing in base are added.
+func mergeVersionResponse(base, overlay *pbVersion.VersionResponse) {
+	for i, ov := range overlay.Versions {
+		if i >= len(base.Versions) {
+			base.Versions = append(base.Versions, ov)
+			continue
+		}
+
+		bv := base.Versions[i]
+		if ov.Product != "" {
+			bv.Product = ov.Product
+		}
+		if ov.Operator != "" {
+			bv.Operator = ov.Operator
+		}
+		if ov.SupportedKubeVersions != "" {
+			bv.SupportedKubeVersions = ov.SupportedKubeVersions
+		}
+		if len(ov.SupportedPlatforms) > 0 {
+			bv.SupportedPlatforms = ov.SupportedPlatforms
+		}
+		if ov.PublishAt != nil {
+			bv.PublishAt = ov.PublishAt
+		}
+		if ov.Matrix == nil {
+			continue
+		}
+		if bv.Matrix == nil {
+			bv.Matrix = ov.Matrix
+			continue
+		}
+
+		bm := bv.Matrix.ProtoReflect()
+		ov.Matrix.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+			if matrixField(string(fd.Name())) == nil {
+				return true
+			}
+			component := bm.Mutable(fd).Map()
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				if !component.Has(k) {
+					component.Set(k, mv)
+					return true
+				}
+				mergeVersion(component.Get(k).Message().Interface().(*pbVersion.Version), mv.Message().Interface().(*pbVersion.Version))
+				return true
+			})
+			return true
+		})
+		for name, oc := range ov.Matrix.Components {
+			bc, ok := bv.Matrix.Components[name]
+			if !ok {
+				if bv.Matrix.Components == nil {
+					bv.Matrix.Components = make(map[string]*pbVersion.ComponentVersions)
+				}
+				bv.Matrix.Components[name] = oc
+				continue
+			}
+			if bc.Versions == nil {
+				bc.Versions = make(map[string]*pbVersion.Version, len(oc.Versions))
+			}
+			for k, v := range oc.Versions {
+				if b, ok := bc.Versions[k]; ok {
+					mergeVersion(b, v)
+					continue
+				}
+				bc.Versions[k] = v
+			}
+		}
+	}
+}
+
+// mergeVersion merges overlay into base. Supported platforms and rollout stages of overlay
+// replace the ones of base, so an overlay can pause a rollout by setting only its paused_at.
+func mergeVersion(base, overlay *pbVersion.Version) {
+	if len(overlay.SupportedPlatforms) > 0 {
+		base.SupportedPlatforms = nil
+	}
+	if len(overlay.GetRollout().GetStages()) > 0 && base.Rollout != nil {
+		base.Rollout.Stages = nil
+	}
+	proto.Merge(base, overlay)
+}
+
+// mergeDeps merges overlay into base replacing rules of the same component versions.
+func mergeDeps(base, overlay Deps) Deps {
+	if base == nil {
+		base = make(Deps, len(overlay))
+	}
+	for component, rules := range overlay {
+		if len(rules) == 0 {
+			continue
+		}
+		if base[component] == nil {
+			base[component] = make(map[string]interface{}, len(rules))
+		}
+		maps.Copy(base[component], rules)
+	}
+	return base
+}
+
+// layeredFS is a read-only union of filesystems. Files of later layers shadow
+// files with the same path in earlier ones, directories are merged.
+type layeredFS []fs.FS
+
+func newLayeredFS(layers ...fs.FS) layeredFS {
+	l := make(layeredFS, 0, len(layers))
+	for _, fsys := range layers {
+		if fsys != nil {
+			l = append(l, fsys)
+		}
+	}
+	return l
+}
+
+func (l layeredFS) Open(name string) (fs.File, error) {
+	for _, fsys := range slices.Backward(l) {
+		f, err := fsys.Open(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		return f, err
+	}
+
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (l layeredFS) ReadFile(name string) ([]byte, error) {
+	for _, fsys := range slices.Backward(l) {
+		c, err := fs.ReadFile(fsys, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		return c, err
+	}
+
+	return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrNotExist}
+}
+
+func (l layeredFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries := make(map[string]fs.DirEntry)
+	found := false
+	for _, fsys := range l {
+		des, err := fs.ReadDir(fsys, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		found = true
+		for _, de := range des {
+			entries[de.Name()] = de
+		}
+	}
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	res := make([]fs.DirEntry, 0, len(entries))
+	for _, name := range slices.Sorted(maps.Keys(entries)) {
+		res = append(res, entries[name])
+	}
+	return res, nil
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+)
+
+func TestNewLayered(t *testing.T) {
+	t.Parallel()
+
+	overlay := Layer{
+		Name: "overlay",
+		Sources: MemorySourceStore{
+			"operator.1.0.0.psmdb-operator.json": []byte(`{
+  "versions": [
+    {
+      "matrix": {
+        "mongod": {
+          "7.0.15-9": {"status": "disabled"},
+          "7.0.16-10": {"image_path": "mirror/percona-server-mongodb:7.0.16-10", "status": "recommended"}
+        }
+      }
+    }
+  ]
+}`),
+			"operator.1.0.0.psmdb-operator.dep.json": []byte(`{
+  "backup": {
+    "2.7.0": {">=": [{"var": "productVersion"}, "7.0"]}
+  }
+}`),
+		},
+		Metadata: fstest.MapFS{
+			"everest/1.0.0.yaml": {Data: []byte("version: 1.0.0\nrecommended:\n  cli: 1.0.1\n")},
+		},
+		ReleaseNotes: fstest.MapFS{
+			"pmm/3.1.0.md": {Data: []byte("### PMM 3.1.0\n")},
+		},
+	}
+	base := Layer{
+		Sources: testSources,
+		Metadata: fstest.MapFS{
+			"everest/0.9.0.yaml": {Data: []byte("version: 0.9.0\n")},
+			"everest/1.0.0.yaml": {Data: []byte("version: 1.0.0\nrecommended:\n  cli: 1.0.0\n")},
+		},
+		ReleaseNotes: fstest.MapFS{
+			"pmm/3.0.0.md": {Data: []byte("### PMM 3.0.0\n")},
+		},
+	}
+
+	b, err := NewLayered([]Layer{base, overlay})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	resp, err := b.Operator(ctx, &pbVersion.OperatorRequest{Product: "psmdb-operator", OperatorVersion: "1.0.0"})
+	require.NoError(t, err)
+	mongod := resp.Versions[0].Matrix.Mongod
+	require.Len(t, mongod, 4)
+	assert.Equal(t, pbVersion.Status_disabled, mongod["7.0.15-9"].Status)
+	assert.Equal(t, "percona/percona-server-mongodb:7.0.15-9", mongod["7.0.15-9"].ImagePath)
+	assert.Equal(t, "mirror/percona-server-mongodb:7.0.16-10", mongod["7.0.16-10"].ImagePath)
+	assert.Equal(t, "1.0.0", resp.Versions[0].Operator)
+	assert.Len(t, resp.Versions[0].Matrix.Backup, 2)
+
+	applied, err := b.Apply(ctx, &pbVersion.ApplyRequest{Product: "psmdb-operator", OperatorVersion: "1.0.0", Apply: "latest"})
+	require.NoError(t, err)
+	assert.Contains(t, applied.Versions[0].Matrix.Backup, "2.8.0")
+
+	meta, err := b.Metadata(ctx, &pbVersion.MetadataRequest{Product: "everest"})
+	require.NoError(t, err)
+	require.Len(t, meta.Versions, 2)
+	assert.Equal(t, "1.0.1", meta.Versions[1].Recommended["cli"])
+
+	for _, v := range []string{"3.0.0", "3.1.0"} {
+		_, err := b.GetReleaseNotes(ctx, &pbVersion.GetReleaseNotesRequest{Product: "pmm", Version: v})
+		assert.NoError(t, err, v)
+	}
+}
+
+func TestReadSources_layeredValidation(t *testing.T) {
+	t.Parallel()
+
+	base := Layer{
+		Name: "base",
+		Sources: MemorySourceStore{
+			"operator.1.0.0.psmdb-operator.json": []byte(`{
+  "versions": [
+    {
+      "matrix": {
+        "mongod": {
+          "7.0.14-8": {"image_path": "percona/percona-server-mongodb:7.0.14-8"},
+          "7.0.15-9": {"image_path": "percona/percona-server-mongodb:7.0.15-9", "status": "recommended"}
+        }
+      }
+    }
+  ]
+}`),
+		},
+	}
+	overlay := Layer{
+		Name: "overlay",
+		Sources: MemorySourceStore{
+			"operator.1.0.0.psmdb-operator.json": []byte(`{
+  "versions": [
+    {
+      "matrix": {
+        "mongod": {
+          "7.0.14-8": {"image_path": "mirror/percona-server-mongodb:7.0.14-8"},
+          "7.0.15-9": {"supported_kube_versions": ">= one", "withdrawn": {"reason": "crash", "replacement": "7.0.16-10"}},
+          "7.0.17-11": {"image_path": "mirror/percona-server-mongodb:7.0.17-11"}
+        }
+      }
+    }
+  ]
+}`),
+			"operator.1.0.0.psmdb-operator.dep.json": []byte(`{
+  "backup": {
+    "2.7.0": {"unknown": [{"var": "productVersion"}, "8.0"]}
+  }
+}`),
+		},
+	}
+
+	report := &ValidationReport{}
+	_, err := readSources([]Layer{base, overlay}, report)
+	require.NoError(t, err)
+	require.Error(t, report.err())
+
+	type issue struct{ file, key string }
+	got := make([]issue, 0, len(report.Issues))
+	for _, i := range report.Issues {
+		got = append(got, issue{i.File, i.Key})
+	}
+	assert.Equal(t, []issue{
+		{"base/operator.1.0.0.psmdb-operator.json", "matrix.mongod.7.0.14-8"},
+		{"overlay/operator.1.0.0.psmdb-operator.dep.json", "backup.2.7.0"},
+		{"overlay/operator.1.0.0.psmdb-operator.json", "matrix.mongod.7.0.15-9.supported_kube_versions"},
+		{"overlay/operator.1.0.0.psmdb-operator.json", "matrix.mongod.7.0.15-9.withdrawn.replacement"},
+		{"overlay/operator.1.0.0.psmdb-operator.json", "matrix.mongod.7.0.17-11"},
+	}, got)
+}
+
+func TestNewLayered_validation(t *testing.T) {
+	t.Parallel()
+
+	overlay := Layer{
+		Name:         "overlay",
+		Metadata:     fstest.MapFS{"everest/1.1.0.yaml": {Data: []byte("version: one\n")}},
+		ReleaseNotes: fstest.MapFS{"pmm/3.1.0.txt": {Data: []byte("PMM 3.1.0\n")}},
+	}
+	base := Layer{
+		Name:     "base",
+		Sources:  testSources,
+		Metadata: fstest.MapFS{"everest/1.0.0.yaml": {Data: []byte("version: 1.0.0\nsupported:\n  pg: \">= foo\"\n")}},
+	}
+
+	_, err := NewLayered([]Layer{base, overlay})
+	var report *ValidationReport
+	require.True(t, errors.As(err, &report))
+
+	type issue struct{ file, key string }
+	got := make([]issue, 0, len(report.Issues))
+	for _, i := range report.Issues {
+		got = append(got, issue{i.File, i.Key})
+	}
+	assert.Equal(t, []issue{
+		{"base/everest/1.0.0.yaml", "supported.pg"},
+		{"overlay/everest/1.1.0.yaml", "version"},
+		{"overlay/pmm/3.1.0.txt", ""},
+	}, got)
+}
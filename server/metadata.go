@@ -98,6 +98,7 @@ func (m *Metadata) getAllMetadataFromFiles(product string) ([]*pbVersion.Metadat
 			Version:     metaV.Version,
 			Recommended: metaV.Recommended,
 			Supported:   metaV.Supported,
+			PublishAt:   metaV.PublishAt,
 		})
 	}
 	return v1Data, v2Data, nil
@@ -1,19 +1,44 @@
 package server
 
-type Deps struct {
-	Backup         map[string]interface{} `json:"backup,omitempty"`
-	PMM            map[string]interface{} `json:"pmm,omitempty"`
-	ProxySQL       map[string]interface{} `json:"proxy_sql,omitempty"`
-	Haproxy        map[string]interface{} `json:"haproxy,omitempty"`
-	LogCollector   map[string]interface{} `json:"logCollector,omitempty"`
-	PgBackrest     map[string]interface{} `json:"pgbackrest,omitempty"`
-	PgBackrestRepo map[string]interface{} `json:"pgbackrest_repo,omitempty"`
-	Pgbadger       map[string]interface{} `json:"pgbadger,omitempty"`
-	Pgbouncer      map[string]interface{} `json:"pgbouncer,omitempty"`
-	PgUpgrade      map[string]interface{} `json:"pgupgrade,omitempty"`
-	Postgis        map[string]interface{} `json:"postgis,omitempty"`
-	Orchestrator   map[string]interface{} `json:"orchestrator,omitempty"`
-	Router         map[string]interface{} `json:"router,omitempty"`
-	Toolkit        map[string]interface{} `json:"toolkit,omitempty"`
-	BinlogServer   map[string]interface{} `json:"binlog_server,omitempty"`
+import (
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Deps holds dependency rules keyed by component and version, e.g. backup and 2.8.0.
+// A rule is a jsonlogic expression evaluated against the selected database version.
+type Deps map[string]map[string]interface{}
+
+// forEachVersion calls fn for every version of every component in the matrices of versions.
+func forEachVersion(versions []*pbVersion.OperatorVersion, fn func(component, version string, v *pbVersion.Version)) {
+	for _, ov := range versions {
+		if ov.Matrix == nil {
+			continue
+		}
+		rangeMatrix(ov.Matrix, func(component string, versions map[string]*pbVersion.Version) {
+			for k, v := range versions {
+				fn(component, k, v)
+			}
+		})
+	}
+}
+
+// rangeMatrix calls fn with versions of every component of the matrix,
+// including the ones without a dedicated VersionMatrix field.
+func rangeMatrix(m *pbVersion.VersionMatrix, fn func(component string, versions map[string]*pbVersion.Version)) {
+	m.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, val protoreflect.Value) bool {
+		if matrixField(string(fd.Name())) == nil {
+			return true
+		}
+		versions := make(map[string]*pbVersion.Version, val.Map().Len())
+		val.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+			versions[k.String()] = mv.Message().Interface().(*pbVersion.Version)
+			return true
+		})
+		fn(string(fd.Name()), versions)
+		return true
+	})
+	for component, c := range m.Components {
+		fn(component, c.GetVersions())
+	}
 }
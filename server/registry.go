@@ -0,0 +1,161 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+)
+
+// RegistryHeader is the gRPC metadata key used to select registry rewrite rules
+// when they are not set in the request.
+const RegistryHeader = "x-registry-mirror"
+
+const defaultRegistry = "docker.io"
+
+// RegistryRewrites holds named sets of rules rewriting image paths to mirror registries.
+type RegistryRewrites struct {
+	// Default is the name of the set applied when a request does not select any.
+	Default string                        `json:"default,omitempty"`
+	Sets    map[string]RegistryRewriteSet `json:"sets"`
+}
+
+// RegistryRewriteSet is a set of rules applied together.
+type RegistryRewriteSet struct {
+	Rules []RegistryRule `json:"rules"`
+	// Digest makes image paths reference images by image_hash instead of tag.
+	Digest bool `json:"digest,omitempty"`
+}
+
+// RegistryRule maps a source registry or repository to its mirror.
+type RegistryRule struct {
+	// From is a registry optionally followed by a repository path, e.g. docker.io or docker.io/percona.
+	// Images without registry are considered to be from docker.io.
+	From string `json:"from"`
+	// To replaces the matched part of the image path, e.g. registry.example.com/percona.
+	To string `json:"to"`
+}
+
+// ReadRegistryRewrites reads registry rewrite rules from a JSON file.
+func ReadRegistryRewrites(file string) (*RegistryRewrites, error) {
+	c, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &RegistryRewrites{}
+	if err := json.Unmarshal(c, r); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal registry rewrites %s: %w", file, err)
+	}
+	if _, ok := r.Sets[r.Default]; r.Default != "" && !ok {
+		return nil, fmt.Errorf("default registry rewrites %s are not defined", r.Default)
+	}
+	for name, set := range r.Sets {
+		for _, rule := range set.Rules {
+			if rule.From == "" || rule.To == "" {
+				return nil, fmt.Errorf("registry rewrites %s: rule must have both from and to", name)
+			}
+		}
+	}
+
+	return r, nil
+}
+
+// set returns the rules selected by name, the X-Registry-Mirror header or the default ones.
+// It returns nil if nothing should be rewritten.
+func (r *RegistryRewrites) set(ctx context.Context, name string) (*RegistryRewriteSet, error) {
+	if r == nil {
+		if name != "" {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown registry: %s", name)
+		}
+		return nil, nil
+	}
+
+	if name == "" {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if v := md.Get(RegistryHeader); len(v) > 0 {
+				name = v[0]
+			}
+		}
+	}
+	if name == "" {
+		name = r.Default
+	}
+	if name == "" {
+		return nil, nil
+	}
+
+	set, ok := r.Sets[name]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown registry: %s", name)
+	}
+	return &set, nil
+}
+
+// rewrite rewrites image paths of all component versions and their distribution images.
+func (s *RegistryRewriteSet) rewrite(versions []*pbVersion.OperatorVersion) {
+	if s == nil {
+		return
+	}
+
+	forEachVersion(versions, func(_, _ string, v *pbVersion.Version) {
+		v.ImagePath = s.rewriteImagePath(v.ImagePath, v.ImageHash)
+		for _, d := range v.Distributions {
+			d.ImagePath = s.rewriteImagePath(d.ImagePath, d.ImageHash)
+		}
+	})
+}
+
+func (s *RegistryRewriteSet) rewriteImagePath(imagePath, hash string) string {
+	if imagePath == "" {
+		return imagePath
+	}
+
+	name, ref := splitImageRef(imagePath)
+	full := normalizeImageName(name)
+
+	matched := ""
+	for _, rule := range s.Rules {
+		from := normalizeImageName(strings.TrimSuffix(rule.From, "/"))
+		if full != from && !strings.HasPrefix(full, from+"/") {
+			continue
+		}
+		if len(from) > len(matched) {
+			matched = from
+			name = strings.TrimSuffix(rule.To, "/") + strings.TrimPrefix(full, from)
+		}
+	}
+
+	if s.Digest && hash != "" {
+		return name + "@sha256:" + strings.TrimPrefix(hash, "sha256:")
+	}
+	return name + ref
+}
+
+// splitImageRef splits image path into its name and tag or digest reference
+// including the separator, e.g. percona/pmm-client:2.44.0 into percona/pmm-client and :2.44.0.
+func splitImageRef(imagePath string) (string, string) {
+	if i := strings.Index(imagePath, "@"); i >= 0 {
+		return imagePath[:i], imagePath[i:]
+	}
+	if i := strings.LastIndex(imagePath, ":"); i > strings.LastIndex(imagePath, "/") {
+		return imagePath[:i], imagePath[i:]
+	}
+	return imagePath, ""
+}
+
+// normalizeImageName adds the default registry to image names without one.
+func normalizeImageName(name string) string {
+	first, _, _ := strings.Cut(name, "/")
+	if strings.ContainsAny(first, ".:") || first == "localhost" {
+		return name
+	}
+	return defaultRegistry + "/" + name
+}
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+)
+
+func TestRegistryRewriteSet_rewriteImagePath(t *testing.T) {
+	t.Parallel()
+
+	set := &RegistryRewriteSet{
+		Rules: []RegistryRule{
+			{From: "docker.io", To: "mirror.example.com/dockerhub"},
+			{From: "percona/percona-server-mongodb", To: "mirror.example.com/psmdb"},
+			{From: "quay.io/percona", To: "mirror.example.com/quay-percona/"},
+		},
+	}
+	digest := &RegistryRewriteSet{Rules: set.Rules, Digest: true}
+
+	tests := map[string]struct {
+		set       *RegistryRewriteSet
+		imagePath string
+		hash      string
+		want      string
+	}{
+		"implicit docker.io registry": {
+			set:       set,
+			imagePath: "percona/pmm-client:2.44.0",
+			want:      "mirror.example.com/dockerhub/percona/pmm-client:2.44.0",
+		},
+		"explicit docker.io registry": {
+			set:       set,
+			imagePath: "docker.io/percona/pmm-client:2.44.0",
+			want:      "mirror.example.com/dockerhub/percona/pmm-client:2.44.0",
+		},
+		"longest match wins": {
+			set:       set,
+			imagePath: "percona/percona-server-mongodb:8.0.4-1-multi",
+			want:      "mirror.example.com/psmdb:8.0.4-1-multi",
+		},
+		"repository prefix matches whole path components": {
+			set:       set,
+			imagePath: "quay.io/percona-lab/pmm-client:3.1.0",
+			want:      "quay.io/percona-lab/pmm-client:3.1.0",
+		},
+		"other registry": {
+			set:       set,
+			imagePath: "quay.io/percona/pmm-client:3.1.0",
+			want:      "mirror.example.com/quay-percona/pmm-client:3.1.0",
+		},
+		"digest reference": {
+			set:       digest,
+			imagePath: "percona/percona-server-mongodb:8.0.4-1-multi",
+			hash:      "873b201ce3d66d97b1225c26db392c5043a73cc19ee8db6f2dc1b8efd4783bcf",
+			want:      "mirror.example.com/psmdb@sha256:873b201ce3d66d97b1225c26db392c5043a73cc19ee8db6f2dc1b8efd4783bcf",
+		},
+		"digest reference with prefixed hash": {
+			set:       digest,
+			imagePath: "percona/pmm-server:3.0.0",
+			hash:      "sha256:7e2552a8628e8e83bfb88ae6aa0496d68b5e6d0b50b0457788a1d02ad3c9d736",
+			want:      "mirror.example.com/dockerhub/percona/pmm-server@sha256:7e2552a8628e8e83bfb88ae6aa0496d68b5e6d0b50b0457788a1d02ad3c9d736",
+		},
+		"digest requested without hash keeps tag": {
+			set:       digest,
+			imagePath: "percona/pmm-server:3.0.0",
+			want:      "mirror.example.com/dockerhub/percona/pmm-server:3.0.0",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.set.rewriteImagePath(tt.imagePath, tt.hash))
+		})
+	}
+}
+
+func TestBackend_registryRewrites(t *testing.T) {
+	t.Parallel()
+
+	registries := &RegistryRewrites{
+		Default: "mirror",
+		Sets: map[string]RegistryRewriteSet{
+			"mirror":  {Rules: []RegistryRule{{From: "percona", To: "mirror.example.com/percona"}}},
+			"private": {Rules: []RegistryRule{{From: "percona", To: "private.example.com/percona"}}},
+		},
+	}
+	b, err := New(testSources, fstest.MapFS{}, fstest.MapFS{}, WithRegistryRewrites(registries))
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		ctx      context.Context
+		registry string
+		want     string
+		code     codes.Code
+	}{
+		"default": {
+			ctx:  context.Background(),
+			want: "mirror.example.com/percona/percona-server-mongodb:8.0.4-1",
+		},
+		"request field": {
+			ctx:      context.Background(),
+			registry: "private",
+			want:     "private.example.com/percona/percona-server-mongodb:8.0.4-1",
+		},
+		"header": {
+			ctx:  metadata.NewIncomingContext(context.Background(), metadata.Pairs(RegistryHeader, "private")),
+			want: "private.example.com/percona/percona-server-mongodb:8.0.4-1",
+		},
+		"unknown": {
+			ctx:      context.Background(),
+			registry: "unknown",
+			code:     codes.InvalidArgument,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resp, err := b.Apply(tt.ctx, &pbVersion.ApplyRequest{
+				Product:         "psmdb-operator",
+				OperatorVersion: "1.0.0",
+				Apply:           "latest",
+				Registry:        tt.registry,
+			})
+			if tt.code != codes.OK {
+				assert.Equal(t, tt.code, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, resp.Versions[0].Matrix.Mongod["8.0.4-1"].ImagePath)
+		})
+	}
+}
+
+func TestRegistryRewriteSet_rewriteDistributions(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		digest bool
+		want   string
+	}{
+		"tag":    {want: "mirror.example.com/percona/postgres-community:16.4"},
+		"digest": {digest: true, want: "mirror.example.com/percona/postgres-community@sha256:community"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			versions := []*pbVersion.OperatorVersion{{Matrix: &pbVersion.VersionMatrix{
+				Postgresql: map[string]*pbVersion.Version{"16.4": {
+					ImagePath: "percona/percona-postgresql:16.4",
+					ImageHash: "percona",
+					Distributions: map[string]*pbVersion.DistributionImage{
+						"community": {ImagePath: "percona/postgres-community:16.4", ImageHash: "community"},
+					},
+				}},
+			}}}
+			set := &RegistryRewriteSet{
+				Rules:  []RegistryRule{{From: "percona", To: "mirror.example.com/percona"}},
+				Digest: tt.digest,
+			}
+			set.rewrite(versions)
+			assert.Equal(t, tt.want, versions[0].Matrix.Postgresql["16.4"].Distributions["community"].ImagePath)
+		})
+	}
+}
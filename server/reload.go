@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// snapshot is a consistent view of all the data served by the Backend.
+// It is never modified after creation, so a reload replaces it as a whole.
+type snapshot struct {
+	sources      *sources
+	metadata     *Metadata
+	releaseNotes *ReleaseNotes
+	// fingerprint is the fingerprint of the files taken before they were read.
+	fingerprint string
+}
+
+func (b *Backend) load() (*snapshot, error) {
+	// taken before reading, so changes made while reading are found by the next check
+	fp, err := b.fingerprint()
+	if err != nil {
+		return nil, err
+	}
+	metadataFS, releaseNotesFS := b.layeredFS()
+
+	report := &ValidationReport{}
+	src, err := readSources(b.layers, report)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range b.layers {
+		if l.Metadata != nil {
+			validateMetadata(l.Name, l.Metadata, report)
+		}
+		if l.ReleaseNotes != nil {
+			validateReleaseNotes(l.Name, l.ReleaseNotes, report)
+		}
+	}
+	if err := report.err(); err != nil {
+		return nil, err
+	}
+
+	m, err := NewMetadata(metadataFS)
+	if err != nil {
+		return nil, err
+	}
+
+	return &snapshot{
+		sources:      src,
+		metadata:     m,
+		releaseNotes: NewReleaseNotes(releaseNotesFS),
+		fingerprint:  fp,
+	}, nil
+}
+
+// layeredFS returns metadata and release notes filesystems of all layers.
+func (b *Backend) layeredFS() (fs.FS, fs.FS) {
+	metadata := make([]fs.FS, 0, len(b.layers))
+	releaseNotes := make([]fs.FS, 0, len(b.layers))
+	for _, l := range b.layers {
+		metadata = append(metadata, l.Metadata)
+		releaseNotes = append(releaseNotes, l.ReleaseNotes)
+	}
+
+	return newLayeredFS(metadata...), newLayeredFS(releaseNotes...)
+}
+
+// Reload reads all the sources again and atomically swaps them in.
+// If the new sources can not be parsed, the previously loaded ones are kept
+// and the error is returned.
+func (b *Backend) Reload() error {
+	s, err := b.load()
+	if err != nil {
+		return fmt.Errorf("failed to load sources: %w", err)
+	}
+
+	b.snapshot.Store(s)
+	return nil
+}
+
+// Watch checks the sources for changes since they were loaded every interval and reloads them
+// when they change. Failed reloads are logged and the previous sources keep being served.
+// It blocks until ctx is done.
+func (b *Backend) Watch(ctx context.Context, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := b.snapshot.Load().fingerprint
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		fp, err := b.fingerprint()
+		if err != nil {
+			logger.Error("failed to check sources for changes", zap.Error(err))
+			continue
+		}
+		if fp == last {
+			continue
+		}
+		// Remember the fingerprint even if the reload fails, so broken sources
+		// are reported once and not on every tick.
+		last = fp
+
+		if err := b.Reload(); err != nil {
+			logger.Error("failed to reload sources, keep serving previous ones", zap.Error(err))
+			continue
+		}
+		logger.Info("sources reloaded")
+	}
+}
+
+// fingerprint returns a digest of the source files content and of names,
+// sizes and modification times of metadata and release notes files of all layers.
+func (b *Backend) fingerprint() (string, error) {
+	h := sha256.New()
+	for i, l := range b.layers {
+		fmt.Fprintf(h, "layer %d\n", i)
+
+		if l.Sources != nil {
+			files, err := l.Sources.ReadAll()
+			if err != nil {
+				return "", err
+			}
+			for _, name := range slices.Sorted(maps.Keys(files)) {
+				fmt.Fprintf(h, "%s\x00%x\n", name, sha256.Sum256(files[name]))
+			}
+		}
+
+		for _, fsys := range []fs.FS{l.Metadata, l.ReleaseNotes} {
+			if fsys == nil {
+				continue
+			}
+			err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				info, err := d.Info()
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Fprintf(h, "%s\x00%d\x00%d\n", p, info.Size(), info.ModTime().UnixNano())
+				return err
+			})
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return "", err
+			}
+		}
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
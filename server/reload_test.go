@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+)
+
+const testOperatorSource = `{
+  "versions": [
+    {
+      "operator": "1.0.0",
+      "product": "test-operator",
+      "matrix": {
+        "mongod": {
+          "%s": {
+            "image_path": "percona/percona-server-mongodb:%s",
+            "status": "recommended"
+          }
+        }
+      }
+    }
+  ]
+}`
+
+func writeTestSource(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+}
+
+func TestBackend_Reload(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestSource(t, dir, "operator.1.0.0.test-operator.json", fmt.Sprintf(testOperatorSource, "7.0.1", "7.0.1"))
+
+	b, err := New(NewDirSourceStore(dir), os.DirFS(filepath.Join(dir, "metadata")), os.DirFS(filepath.Join(dir, "release-notes")))
+	require.NoError(t, err)
+
+	operatorVersions := func() map[string]*pbVersion.Version {
+		resp, err := b.Operator(context.Background(), &pbVersion.OperatorRequest{Product: "test-operator", OperatorVersion: "1.0.0"})
+		require.NoError(t, err)
+		return resp.Versions[0].Matrix.Mongod
+	}
+	assert.Contains(t, operatorVersions(), "7.0.1")
+
+	t.Run("valid sources are swapped in", func(t *testing.T) {
+		writeTestSource(t, dir, "operator.1.0.0.test-operator.json", fmt.Sprintf(testOperatorSource, "7.0.2", "7.0.2"))
+		require.NoError(t, b.Reload())
+		assert.Contains(t, operatorVersions(), "7.0.2")
+	})
+
+	t.Run("invalid sources keep previous snapshot", func(t *testing.T) {
+		writeTestSource(t, dir, "operator.1.0.0.test-operator.json", `{"versions": [`)
+		assert.Error(t, b.Reload())
+		assert.Contains(t, operatorVersions(), "7.0.2")
+	})
+}
+
+func TestBackend_Watch(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestSource(t, dir, "operator.1.0.0.test-operator.json", fmt.Sprintf(testOperatorSource, "7.0.1", "7.0.1"))
+
+	b, err := New(NewDirSourceStore(dir), os.DirFS(filepath.Join(dir, "metadata")), os.DirFS(filepath.Join(dir, "release-notes")))
+	require.NoError(t, err)
+
+	// changed after loading but before watching
+	writeTestSource(t, dir, "operator.1.0.0.test-operator.json", fmt.Sprintf(testOperatorSource, "7.0.2", "7.0.2"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go b.Watch(ctx, 10*time.Millisecond, zap.NewNop())
+
+	assert.Eventually(t, func() bool {
+		resp, err := b.Operator(context.Background(), &pbVersion.OperatorRequest{Product: "test-operator", OperatorVersion: "1.0.0"})
+		return err == nil && resp.Versions[0].Matrix.Mongod["7.0.2"] != nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"cmp"
+	"hash/fnv"
+	"time"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+)
+
+// rollout decides which recommended versions being rolled out are recommended to the cluster of a request.
+type rollout struct {
+	// cluster identifies the cluster, it is empty if the request does not identify one.
+	cluster string
+	now     time.Time
+}
+
+// newRollout returns the rollout for the cluster of the request at time now.
+// Clusters are identified by the custom resource or, if it is not set, by the namespace.
+func newRollout(req *pbVersion.ApplyRequest, now time.Time) rollout {
+	return rollout{cluster: cmp.Or(req.CustomResourceUid, req.NamespaceUid), now: now}
+}
+
+// apply returns recommended versions as available to clusters out of their rollout, so the clusters keep
+// the previous recommendation, and records the reason in e. The rollouts are removed from the versions.
+func (r rollout) apply(vs *pbVersion.VersionResponse, e *explanation) {
+	forEachVersion(vs.Versions, func(component, version string, v *pbVersion.Version) {
+		if v.Rollout == nil {
+			return
+		}
+		percent := rolloutPercent(v.Rollout, r.now)
+		v.Rollout = nil
+		if v.Status != pbVersion.Status_recommended || r.included(component, version, percent) {
+			return
+		}
+		v.Status = pbVersion.Status_available
+		e.component(component).drop(version, "recommended to %d%% of clusters, not to this one", percent)
+	})
+}
+
+// included reports whether the cluster is in the first percent of clusters the version is rolled out to.
+// Clusters are placed by a hash of the cluster and the version, so a cluster always gets the same answer
+// for a version, while early rollouts of different versions reach different clusters.
+// Clusters which can not be identified are included only when the rollout is complete.
+func (r rollout) included(component, version string, percent uint32) bool {
+	if percent >= 100 {
+		return true
+	}
+	if r.cluster == "" {
+		return false
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(r.cluster + "/" + component + "/" + version))
+	return h.Sum32()%100 < percent
+}
+
+// rolloutPercent returns the percentage of clusters of the last stage started at time now
+// or at the time the rollout was paused if it was paused earlier.
+func rolloutPercent(r *pbVersion.Rollout, now time.Time) uint32 {
+	if r.PausedAt != nil && r.PausedAt.AsTime().Before(now) {
+		now = r.PausedAt.AsTime()
+	}
+	var percent uint32
+	for _, s := range r.Stages {
+		if s.Start.AsTime().After(now) {
+			break
+		}
+		percent = s.Percent
+	}
+	return percent
+}
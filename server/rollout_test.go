@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+)
+
+func TestRolloutPercent(t *testing.T) {
+	t.Parallel()
+
+	at := func(day int) *timestamppb.Timestamp {
+		return timestamppb.New(time.Date(2026, time.March, day, 0, 0, 0, 0, time.UTC))
+	}
+	stages := []*pbVersion.RolloutStage{
+		{Percent: 5, Start: at(1)},
+		{Percent: 25, Start: at(8)},
+		{Percent: 100, Start: at(15)},
+	}
+
+	tests := map[string]struct {
+		pausedAt *timestamppb.Timestamp
+		now      *timestamppb.Timestamp
+		want     uint32
+	}{
+		"not started":      {now: at(0), want: 0},
+		"first stage":      {now: at(1), want: 5},
+		"second stage":     {now: at(10), want: 25},
+		"complete":         {now: at(20), want: 100},
+		"paused":           {pausedAt: at(3), now: at(20), want: 5},
+		"paused in future": {pausedAt: at(12), now: at(10), want: 25},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r := &pbVersion.Rollout{Stages: stages, PausedAt: tt.pausedAt}
+			assert.Equal(t, tt.want, rolloutPercent(r, tt.now.AsTime()))
+		})
+	}
+}
+
+func TestBackend_rollout(t *testing.T) {
+	t.Parallel()
+
+	overlay := Layer{Sources: MemorySourceStore{
+		"operator.1.0.0.psmdb-operator.json": []byte(`{
+  "versions": [
+    {
+      "matrix": {
+        "mongod": {
+          "7.0.16-10": {
+            "image_path": "percona/percona-server-mongodb:7.0.16-10",
+            "status": "recommended",
+            "rollout": {
+              "stages": [
+                {"percent": 25, "start": "2000-01-01T00:00:00Z"},
+                {"percent": 100, "start": "2100-01-01T00:00:00Z"}
+              ]
+            }
+          }
+        }
+      }
+    }
+  ]
+}`),
+	}}
+	b, err := NewLayered([]Layer{{Sources: testSources}, overlay})
+	require.NoError(t, err)
+
+	// find clusters in and out of the first 25% of the rollout
+	var in, out string
+	for i := 0; in == "" || out == ""; i++ {
+		uid := fmt.Sprintf("cluster-%d", i)
+		if (rollout{cluster: uid}).included("mongod", "7.0.16-10", 25) {
+			in = uid
+		} else {
+			out = uid
+		}
+	}
+
+	tests := map[string]struct {
+		customResourceUID string
+		namespaceUID      string
+		want              string
+		wantReason        string
+	}{
+		"in rollout":           {customResourceUID: in, want: "7.0.16-10"},
+		"namespace in rollout": {namespaceUID: in, want: "7.0.16-10"},
+		"out of rollout":       {customResourceUID: out, want: "7.0.15-9", wantReason: "recommended to 25% of clusters, not to this one"},
+		"unidentified":         {want: "7.0.15-9", wantReason: "recommended to 25% of clusters, not to this one"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			for range 2 {
+				resp, err := b.Apply(context.Background(), &pbVersion.ApplyRequest{
+					Product:           "psmdb-operator",
+					OperatorVersion:   "1.0.0",
+					Apply:             "recommended",
+					DatabaseVersion:   "7.0.14-8",
+					CustomResourceUid: tt.customResourceUID,
+					NamespaceUid:      tt.namespaceUID,
+					Explain:           true,
+				})
+				require.NoError(t, err)
+
+				mongod := resp.Versions[0].Matrix.Mongod
+				require.Len(t, mongod, 1)
+				require.Contains(t, mongod, tt.want)
+				assert.Nil(t, mongod[tt.want].Rollout)
+
+				for _, c := range resp.Explanation {
+					for _, v := range c.Candidates {
+						if c.Component == "mongod" && v.Version == "7.0.16-10" && !v.Selected {
+							assert.Equal(t, tt.wantReason, v.Reason)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestValidateRollout(t *testing.T) {
+	t.Parallel()
+
+	sources := MemorySourceStore{
+		"operator.1.0.0.psmdb-operator.json": []byte(`{
+  "versions": [
+    {
+      "matrix": {
+        "mongod": {
+          "7.0.16-10": {
+            "status": "recommended",
+            "rollout": {
+              "stages": [
+                {"percent": 5, "start": "2026-03-08T00:00:00Z"},
+                {"percent": 120, "start": "2026-03-01T00:00:00Z"},
+                {"percent": 100}
+              ]
+            }
+          }
+        }
+      }
+    }
+  ]
+}`),
+	}
+
+	assert.Equal(t, []string{
+		"matrix.mongod.7.0.16-10.rollout.stages.1.percent",
+		"matrix.mongod.7.0.16-10.rollout.stages.1.start",
+		"matrix.mongod.7.0.16-10.rollout.stages.2.start",
+	}, validationIssueKeys(t, sources))
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/fs"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/protobuf/jsonpb"
@@ -50,24 +51,73 @@ var (
 
 // Backend implements the protobuf interface.
 type Backend struct {
-	metadata     *Metadata
-	releaseNotes *ReleaseNotes
+	snapshot   atomic.Pointer[snapshot]
+	layers     []Layer
+	registries *RegistryRewrites
+	// embargoToken authenticates requests overriding the embargo of versions before their publish_at.
+	embargoToken string
 	pbVersion.UnimplementedVersionServiceServer
 }
 
+// Option configures optional Backend features.
+type Option func(*Backend)
+
+// WithRegistryRewrites enables rewriting of image paths to mirror registries.
+func WithRegistryRewrites(r *RegistryRewrites) Option {
+	return func(b *Backend) {
+		b.registries = r
+	}
+}
+
+// WithEmbargoOverrideToken lets requests with the token in the X-Embargo-Override header
+// see versions before their publish_at.
+func WithEmbargoOverrideToken(token string) Option {
+	return func(b *Backend) {
+		b.embargoToken = token
+	}
+}
+
 // New initializes a new Backend struct.
-func New(metadata fs.FS, releaseNotes fs.FS) (*Backend, error) {
-	m, err := NewMetadata(metadata)
-	if err != nil {
+func New(sources SourceStore, metadata fs.FS, releaseNotes fs.FS, opts ...Option) (*Backend, error) {
+	return NewLayered([]Layer{{Sources: sources, Metadata: metadata, ReleaseNotes: releaseNotes}}, opts...)
+}
+
+// NewLayered initializes a new Backend struct with sources from multiple layers.
+// Layers are applied in order: later layers add new files and override
+// component versions, metadata and release notes of earlier ones.
+func NewLayered(layers []Layer, opts ...Option) (*Backend, error) {
+	b := &Backend{layers: layers}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if err := b.Reload(); err != nil {
 		return nil, err
 	}
 
-	rn := NewReleaseNotes(releaseNotes)
-	return &Backend{metadata: m, releaseNotes: rn}, nil
+	return b, nil
 }
 
 func (b *Backend) Product(ctx context.Context, req *pbVersion.ProductRequest) (*pbVersion.ProductResponse, error) {
-	return operatorData(req.Product)
+	registry, err := b.registries.set(ctx, req.Registry)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.PageSize < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid page size: %d", req.PageSize)
+	}
+	em, err := b.embargo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := b.snapshot.Load().sources.operatorData(req.Product, req.OperatorVersion, int(req.PageSize), req.PageToken, em)
+	if err != nil {
+		return nil, err
+	}
+	registry.rewrite(resp.Versions)
+
+	return resp, nil
 }
 
 func (b *Backend) Operator(ctx context.Context, req *pbVersion.OperatorRequest) (*pbVersion.OperatorResponse, error) {
@@ -75,11 +125,35 @@ func (b *Backend) Operator(ctx context.Context, req *pbVersion.OperatorRequest)
 	if req.Product == pmmServerProduct {
 		productFamily = "pmm"
 	}
-	vs, err := operatorProductData(productFamily, req.Product, req.OperatorVersion)
+	registry, err := b.registries.set(ctx, req.Registry)
+	if err != nil {
+		return nil, err
+	}
+	em, err := b.embargo(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	src := b.snapshot.Load().sources
+	operatorVersion, err := src.resolveOperatorVersion(productFamily, req.Product, req.OperatorVersion, em)
+	if err != nil {
+		return nil, err
+	}
+	vs, err := src.operatorProductData(productFamily, req.Product, operatorVersion)
+	if err != nil {
+		return nil, err
+	}
+	vs.Versions = em.filter(vs.Versions)
+
+	compat, err := newCompatibility(req.KubeVersion, req.Platform, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := compat.filter(vs, nil, src.components[req.Product].Database); err != nil {
+		return nil, err
+	}
+	registry.rewrite(vs.Versions)
+
 	return &pbVersion.OperatorResponse{
 		Versions: vs.Versions,
 	}, nil
@@ -106,294 +180,84 @@ func (b *Backend) Apply(ctx context.Context, req *pbVersion.ApplyRequest) (*pbVe
 		return &pbVersion.VersionResponse{}, nil
 	}
 
-	err := transformRequest(req)
+	registry, err := b.registries.set(ctx, req.Registry)
 	if err != nil {
 		return nil, err
 	}
 
-	vs, err := operatorProductData("operator", req.Product, req.OperatorVersion)
+	em, err := b.embargo(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	deps, err := getDep(req.Product, req.OperatorVersion)
+	req.Explain = explainRequested(ctx, req.Explain)
+	vs, err := b.snapshot.Load().sources.apply(req, em)
 	if err != nil {
 		return nil, err
 	}
-
-	switch req.Product {
-	case "pxc-operator":
-		err := pxc(vs, deps, req)
-		if err != nil {
-			return nil, err
-		}
-	case "psmdb-operator":
-		err := psmdb(vs, deps, req)
-		if err != nil {
-			return nil, err
-		}
-	case "pg-operator":
-		err := pg(vs, deps, req)
-		if err != nil {
-			return nil, err
-		}
-	case "ps-operator":
-		err := ps(vs, deps, req)
-		if err != nil {
-			return nil, err
-		}
-	default:
-		return nil, status.Errorf(codes.InvalidArgument, "invalid product: %s", req.Product)
-	}
+	registry.rewrite(vs.Versions)
 
 	return vs, nil
 }
 
-func (b *Backend) Metadata(ctx context.Context, req *pbVersion.MetadataRequest) (*pbVersion.MetadataResponse, error) {
-	return b.metadata.Product(req.Product)
-}
-
-func (b *Backend) MetadataV2(ctx context.Context, req *pbVersion.MetadataRequest) (*pbVersion.MetadataV2Response, error) {
-	return b.metadata.ProductV2(req.Product)
-}
-
-func (b *Backend) GetReleaseNotes(ctx context.Context, req *pbVersion.GetReleaseNotesRequest) (*pbVersion.GetReleaseNotesResponse, error) {
-	return b.releaseNotes.GetReleaseNote(req.Product, req.Version)
-}
-
-func pxc(vs *pbVersion.VersionResponse, deps Deps, req *pbVersion.ApplyRequest) error {
-	err := pxcFilter(vs.Versions[0].Matrix.Pxc, req.Apply, req.DatabaseVersion)
-	if err != nil {
-		return err
-	}
-
-	productVersion := ""
-	for k := range vs.Versions[0].Matrix.Pxc {
-		productVersion = k
-		break
-	}
-
-	backupVersion, err := depFilter(deps.Backup, productVersion)
+func (b *Backend) PlanUpgrade(ctx context.Context, req *pbVersion.PlanUpgradeRequest) (*pbVersion.PlanUpgradeResponse, error) {
+	registry, err := b.registries.set(ctx, req.Registry)
 	if err != nil {
-		return err
-	}
-	err = defaultFilter(vs.Versions[0].Matrix.Backup, backupVersion, true)
-	if err != nil {
-		return err
-	}
-
-	err = pmmFilter(vs.Versions[0].Matrix.Pmm, true)
-	if err != nil {
-		return err
-	}
-
-	proxySQL, err := depFilter(deps.ProxySQL, productVersion)
-	if err != nil {
-		return err
-	}
-	err = defaultFilter(vs.Versions[0].Matrix.Proxysql, proxySQL, false)
-	if err != nil {
-		return err
+		return nil, err
 	}
 
-	haproxy, err := depFilter(deps.Haproxy, productVersion)
-	if err != nil {
-		return err
-	}
-	err = defaultFilter(vs.Versions[0].Matrix.Haproxy, haproxy, true)
+	em, err := b.embargo(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	logCollectorVersion, err := depFilter(deps.LogCollector, productVersion)
+	hops, err := b.snapshot.Load().sources.planUpgrade(req, em)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	err = defaultFilter(vs.Versions[0].Matrix.LogCollector, logCollectorVersion, false)
-	if err != nil {
-		return err
+	for _, hop := range hops {
+		registry.rewrite([]*pbVersion.OperatorVersion{{Matrix: hop.Matrix}})
 	}
 
-	return nil
+	return &pbVersion.PlanUpgradeResponse{Hops: hops}, nil
 }
 
-func psmdb(vs *pbVersion.VersionResponse, deps Deps, req *pbVersion.ApplyRequest) error {
-	err := psmdbFilter(vs.Versions[0].Matrix.Mongod, req.Apply, req.DatabaseVersion)
-	if err != nil {
-		return err
-	}
-
-	productVersion := ""
-	for k := range vs.Versions[0].Matrix.Mongod {
-		productVersion = k
-		break
-	}
-
-	backupVersion, err := depFilter(deps.Backup, productVersion)
-	if err != nil {
-		return err
-	}
-	err = defaultFilter(vs.Versions[0].Matrix.Backup, backupVersion, true)
+func (b *Backend) Metadata(ctx context.Context, req *pbVersion.MetadataRequest) (*pbVersion.MetadataResponse, error) {
+	em, err := b.embargo(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	err = pmmFilter(vs.Versions[0].Matrix.Pmm, true)
+	resp, err := b.snapshot.Load().metadata.Product(req.Product)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	return nil
+	return &pbVersion.MetadataResponse{Versions: filterMetadata(em, resp.Versions)}, nil
 }
 
-func pg(vs *pbVersion.VersionResponse, deps Deps, req *pbVersion.ApplyRequest) error {
-	err := pgFilter(vs.Versions[0].Matrix.Postgresql, req.Apply, req.DatabaseVersion)
-	if err != nil {
-		return err
-	}
-
-	productVersion := ""
-	for k := range vs.Versions[0].Matrix.Postgresql {
-		productVersion = k
-		break
-	}
-
-	depVer, err := pgDepFilter(deps.PgBackrest, productVersion)
-	if err != nil {
-		return err
-	}
-	err = defaultFilter(vs.Versions[0].Matrix.Pgbackrest, depVer, true)
-	if err != nil {
-		return err
-	}
-
-	depVer, err = pgDepFilter(deps.PgBackrestRepo, productVersion)
-	if err != nil {
-		return err
-	}
-	err = defaultFilter(vs.Versions[0].Matrix.PgbackrestRepo, depVer, true)
-	if err != nil {
-		return err
-	}
-
-	depVer, err = pgDepFilter(deps.Pgbadger, productVersion)
-	if err != nil {
-		return err
-	}
-	err = defaultFilter(vs.Versions[0].Matrix.Pgbadger, depVer, true)
-	if err != nil {
-		return err
-	}
-
-	depVer, err = pgDepFilter(deps.Pgbouncer, productVersion)
-	if err != nil {
-		return err
-	}
-	err = defaultFilter(vs.Versions[0].Matrix.Pgbouncer, depVer, true)
-	if err != nil {
-		return err
-	}
-
-	depVer, err = pgDepFilter(deps.PgUpgrade, productVersion)
-	if err != nil {
-		return err
-	}
-	err = defaultFilter(vs.Versions[0].Matrix.Pgupgrade, depVer, true)
-	if err != nil {
-		return err
-	}
-
-	depVer, err = pgDepFilter(deps.Postgis, productVersion)
-	if err != nil {
-		return err
-	}
-	err = defaultFilter(vs.Versions[0].Matrix.Postgis, depVer, true)
+func (b *Backend) MetadataV2(ctx context.Context, req *pbVersion.MetadataRequest) (*pbVersion.MetadataV2Response, error) {
+	em, err := b.embargo(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	err = pmmFilter(vs.Versions[0].Matrix.Pmm, true)
+	resp, err := b.snapshot.Load().metadata.ProductV2(req.Product)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	return nil
+	return &pbVersion.MetadataV2Response{Versions: filterMetadata(em, resp.Versions)}, nil
 }
 
-func ps(vs *pbVersion.VersionResponse, deps Deps, req *pbVersion.ApplyRequest) error {
-	err := psFilter(vs.Versions[0].Matrix.Mysql, req.Apply, req.DatabaseVersion)
-	if err != nil {
-		return err
-	}
-
-	productVersion := ""
-	for k := range vs.Versions[0].Matrix.Mysql {
-		productVersion = k
-		break
-	}
-
-	backupVersion, err := depFilter(deps.Backup, productVersion)
-	if err != nil {
-		return err
-	}
-	err = defaultFilter(vs.Versions[0].Matrix.Backup, backupVersion, true)
-	if err != nil {
-		return err
-	}
+func (b *Backend) GetReleaseNotes(ctx context.Context, req *pbVersion.GetReleaseNotesRequest) (*pbVersion.GetReleaseNotesResponse, error) {
+	return b.snapshot.Load().releaseNotes.GetReleaseNote(req.Product, req.Version)
+}
 
-	pmmVersion, err := depFilter(deps.PMM, productVersion)
-	if err != nil {
-		return err
-	}
-	err = defaultFilter(vs.Versions[0].Matrix.Pmm, pmmVersion, true)
-	if err != nil {
-		return err
-	}
-	orchestratorVersion, err := depFilter(deps.Orchestrator, productVersion)
-	if err != nil {
-		return err
-	}
-	err = defaultFilter(vs.Versions[0].Matrix.Orchestrator, orchestratorVersion, true)
-	if err != nil {
-		return err
-	}
-	routerVersion, err := depFilter(deps.Router, productVersion)
-	if err != nil {
-		return err
-	}
-	err = defaultFilter(vs.Versions[0].Matrix.Router, routerVersion, true)
-	if err != nil {
-		return err
-	}
-	haproxyVersion, err := depFilter(deps.Haproxy, productVersion)
-	if err != nil {
-		return err
-	}
-	err = defaultFilter(vs.Versions[0].Matrix.Haproxy, haproxyVersion, true)
-	if err != nil {
-		return err
-	}
-	binlogServerVersion, err := depFilter(deps.BinlogServer, productVersion)
-	if err != nil {
-		return err
-	}
-	err = defaultFilter(vs.Versions[0].Matrix.BinlogServer, binlogServerVersion, true)
-	if err != nil {
-		return err
-	}
-	toolkitVersion, err := depFilter(deps.Toolkit, productVersion)
-	if err != nil {
-		return err
+func transformRequest(req *pbVersion.ApplyRequest) error {
+	if strings.HasPrefix(strings.ToLower(req.Apply), nextLinePrefix) {
+		return nil
 	}
-	err = defaultFilter(vs.Versions[0].Matrix.Toolkit, toolkitVersion, true)
-	if err != nil {
-		return err
+	if _, ok := parseVersionRange(req.Apply); ok {
+		return nil
 	}
 
-	return nil
-}
-
-func transformRequest(req *pbVersion.ApplyRequest) error {
 	sep := "-"
 	if strings.HasSuffix(req.Apply, sep+recommended) || strings.HasSuffix(req.Apply, sep+latest) {
 		sp := strings.Split(req.Apply, sep)
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+)
+
+var testSources = MemorySourceStore{
+	"components.json": []byte(`{
+  "psmdb-operator": {
+    "database": "mongod",
+    "policy": "same-minor-highest",
+    "components": [
+      {"name": "backup", "selector": "dep"},
+      {"name": "pmm", "selector": "pmm", "eol": ["2"]}
+    ]
+  }
+}`),
+	"operator.1.0.0.psmdb-operator.json": []byte(`{
+  "versions": [
+    {
+      "operator": "1.0.0",
+      "product": "psmdb-operator",
+      "matrix": {
+        "mongod": {
+          "7.0.14-8": {"image_path": "percona/percona-server-mongodb:7.0.14-8", "status": "available"},
+          "7.0.15-9": {"image_path": "percona/percona-server-mongodb:7.0.15-9", "status": "recommended"},
+          "8.0.4-1": {"image_path": "percona/percona-server-mongodb:8.0.4-1", "status": "available"}
+        },
+        "backup": {
+          "2.7.0": {"image_path": "percona/percona-backup-mongodb:2.7.0", "status": "recommended"},
+          "2.8.0": {"image_path": "percona/percona-backup-mongodb:2.8.0", "status": "recommended"}
+        },
+        "pmm": {
+          "2.44.0": {"image_path": "percona/pmm-client:2.44.0", "status": "recommended"},
+          "3.1.0": {"image_path": "percona/pmm-client:3.1.0", "status": "recommended"}
+        },
+        "operator": {
+          "1.0.0": {"image_path": "percona/percona-server-mongodb-operator:1.0.0", "status": "recommended"}
+        }
+      }
+    }
+  ]
+}`),
+	"operator.1.0.0.psmdb-operator.dep.json": []byte(`{
+  "backup": {
+    "2.8.0": {">=": [{"var": "productVersion"}, "8.0"]},
+    "2.7.0": {"<": [{"var": "productVersion"}, "8.0"]}
+  }
+}`),
+}
+
+func TestBackend_Apply(t *testing.T) {
+	t.Parallel()
+
+	b, err := New(testSources, fstest.MapFS{}, fstest.MapFS{})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		apply           string
+		databaseVersion string
+		wantMongod      string
+		wantBackup      string
+	}{
+		{
+			name:       "recommended without current version",
+			apply:      "recommended",
+			wantMongod: "7.0.15-9",
+			wantBackup: "2.7.0",
+		},
+		{
+			name:       "latest without current version",
+			apply:      "latest",
+			wantMongod: "8.0.4-1",
+			wantBackup: "2.8.0",
+		},
+		{
+			name:            "latest within current minor",
+			apply:           "latest",
+			databaseVersion: "7.0.14-8",
+			wantMongod:      "7.0.15-9",
+			wantBackup:      "2.7.0",
+		},
+		{
+			name:       "exact version",
+			apply:      "8.0.4-1",
+			wantMongod: "8.0.4-1",
+			wantBackup: "2.8.0",
+		},
+		{
+			name:            "latest in range",
+			apply:           "~7.0.14-latest",
+			databaseVersion: "8.0.4-1",
+			wantMongod:      "7.0.15-9",
+			wantBackup:      "2.7.0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			resp, err := b.Apply(context.Background(), &pbVersion.ApplyRequest{
+				Product:         "psmdb-operator",
+				OperatorVersion: "latest",
+				Apply:           tt.apply,
+				DatabaseVersion: tt.databaseVersion,
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, "1.0.0", resp.Versions[0].Operator)
+			matrix := resp.Versions[0].Matrix
+			assert.Len(t, matrix.Mongod, 1)
+			assert.Contains(t, matrix.Mongod, tt.wantMongod)
+			assert.Len(t, matrix.Backup, 1)
+			assert.Contains(t, matrix.Backup, tt.wantBackup)
+			assert.Len(t, matrix.Pmm, 2)
+		})
+	}
+}
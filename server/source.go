@@ -1,105 +1,523 @@
 package server
 
 import (
+	"encoding/base64"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"log"
-	"os"
+	"maps"
 	"path"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/Masterminds/semver"
 	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+	"github.com/bufbuild/protoyaml-go"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
 )
 
-var data = map[string][]byte{}
-var deps = map[string][]byte{}
+const (
+	sourceExt    = ".json"
+	depSourceExt = ".dep.json"
+)
 
-func init() {
-	files, err := os.ReadDir("./sources")
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return
+var majorMinorOnlyRegexp = regexp.MustCompile(`^\d+\.\d+$`)
+
+// sourceExts lists supported extensions of source files, dependency ones go first
+// as they end with the extensions of operator source files.
+var sourceExts = []string{depSourceExt, ".dep.yaml", ".dep.yml", sourceExt, ".yaml", ".yml"}
+
+// sourceKey identifies a source file, e.g. operator.1.19.1.psmdb-operator.json
+// has "operator" family, "psmdb-operator" product and "1.19.1" version.
+type sourceKey struct {
+	family  string
+	product string
+	version string
+}
+
+func (k sourceKey) fileName(ext string) string {
+	return fmt.Sprintf("%s.%s.%s%s", k.family, k.version, k.product, ext)
+}
+
+// parseSourceName splits a source file name into its key and extension.
+func parseSourceName(name string) (sourceKey, string, error) {
+	var base, ext string
+	for _, e := range sourceExts {
+		if b, ok := strings.CutSuffix(name, e); ok {
+			base, ext = b, e
+			break
 		}
-		log.Fatal(err)
 	}
-	for _, file := range files {
-		if file.IsDir() {
-			continue
-		}
+	if ext == "" {
+		return sourceKey{}, "", fmt.Errorf("unsupported source file extension: %s", name)
+	}
+
+	family, rest, ok := strings.Cut(base, ".")
+	if !ok {
+		return sourceKey{}, "", fmt.Errorf("invalid source file name: %s", name)
+	}
+	i := strings.LastIndex(rest, ".")
+	if i <= 0 || i == len(rest)-1 {
+		return sourceKey{}, "", fmt.Errorf("invalid source file name: %s", name)
+	}
+
+	return sourceKey{family: family, version: rest[:i], product: rest[i+1:]}, ext, nil
+}
+
+func isDepSource(ext string) bool {
+	return strings.HasPrefix(ext, ".dep.")
+}
+
+func isYAMLSource(ext string) bool {
+	return strings.HasSuffix(ext, ".yaml") || strings.HasSuffix(ext, ".yml")
+}
 
-		fname := file.Name()
-		content, err := os.ReadFile(path.Join("./sources", fname))
+// unmarshalVersionResponse decodes an operator source file in JSON or YAML format.
+func unmarshalVersionResponse(content []byte, ext string) (*pbVersion.VersionResponse, error) {
+	vs := &pbVersion.VersionResponse{}
+	if isYAMLSource(ext) {
+		return vs, protoyaml.Unmarshal(content, vs)
+	}
+	return vs, protojson.Unmarshal(content, vs)
+}
+
+// unmarshalDeps decodes a dependency source file in JSON or YAML format.
+// YAML is converted to JSON first, keeping unquoted versions as strings, so both formats produce the same rules.
+func unmarshalDeps(content []byte, ext string) (Deps, error) {
+	if isYAMLSource(ext) {
+		c, err := yamlToJSON(content)
 		if err != nil {
-			log.Fatalf("failed to read source file: %v", err)
+			return nil, err
 		}
+		content = c
+	}
 
-		if strings.HasSuffix(fname, ".dep.json") {
-			deps[fname] = content
-			continue
-		}
+	dep := Deps{}
+	err := json.Unmarshal(content, &dep)
+	return dep, err
+}
 
-		data[fname] = content
+// yamlToJSON converts YAML to JSON. Mapping keys and numbers keep their source text as strings,
+// e.g. 16.10 stays "16.10" rather than becoming the number 16.1, as dependency rules compare versions.
+func yamlToJSON(content []byte) ([]byte, error) {
+	var n yaml.Node
+	if err := yaml.Unmarshal(content, &n); err != nil {
+		return nil, err
+	}
+	v, err := yamlValue(&n)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// yamlValue returns the value of a YAML node as decoded from JSON by encoding/json.
+func yamlValue(n *yaml.Node) (interface{}, error) {
+	switch n.Kind {
+	case 0:
+		return nil, nil
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+		return yamlValue(n.Content[0])
+	case yaml.AliasNode:
+		return yamlValue(n.Alias)
+	case yaml.SequenceNode:
+		res := make([]interface{}, 0, len(n.Content))
+		for _, c := range n.Content {
+			v, err := yamlValue(c)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, v)
+		}
+		return res, nil
+	case yaml.MappingNode:
+		res := make(map[string]interface{}, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k := n.Content[i]
+			if k.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("line %d: mapping key is not a scalar", k.Line)
+			}
+			v, err := yamlValue(n.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			res[k.Value] = v
+		}
+		return res, nil
+	case yaml.ScalarNode:
+		switch n.ShortTag() {
+		case "!!null":
+			return nil, nil
+		case "!!bool":
+			var b bool
+			err := n.Decode(&b)
+			return b, err
+		}
+		return n.Value, nil
 	}
+	return nil, fmt.Errorf("line %d: unsupported YAML node", n.Line)
 }
 
-func operatorData(product string) (*pbVersion.ProductResponse, error) {
-	suffix := fmt.Sprintf(".%s.json", product)
-	r := pbVersion.ProductResponse{}
+// sources is an index of parsed operator source and dependency files.
+// Stored values are shared between requests and must not be modified,
+// use the accessors which return copies instead.
+type sources struct {
+	versions map[sourceKey]*pbVersion.VersionResponse
+	deps     map[sourceKey]Deps
+	// depFiles holds the file each dependency rule of deps is read from keyed by component and version,
+	// e.g. backup.2.8.0.
+	depFiles map[sourceKey]map[string]string
+	// rules holds the dependency rules of deps indexed by component.
+	rules map[sourceKey]indexedDeps
+	// components declares components of operator products used by Apply.
+	components ComponentRegistry
+	// products holds operator versions from all source files of a product sorted in ascending order.
+	products map[string][]productVersion
+	// operators holds versions of source files per product family and product sorted in descending order.
+	operators map[sourceKey][]*semver.Version
+}
+
+// readSources reads source files of all layers and parses them into an index.
+// Files of later layers are merged into the files with the same name from earlier ones.
+// Problems with the content of the files are added to report.
+func readSources(layers []Layer, report *ValidationReport) (*sources, error) {
+	origins := make(map[sourceKey]*sourceOrigin)
+	s := &sources{
+		versions:   make(map[sourceKey]*pbVersion.VersionResponse),
+		deps:       make(map[sourceKey]Deps),
+		depFiles:   make(map[sourceKey]map[string]string),
+		rules:      make(map[sourceKey]indexedDeps),
+		components: make(ComponentRegistry),
+		products:   make(map[string][]productVersion),
+		operators:  make(map[sourceKey][]*semver.Version),
+	}
+	for _, l := range layers {
+		if l.Sources == nil {
+			continue
+		}
+		files, err := l.Sources.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+
+		// the same source must not be defined in multiple formats in a layer
+		defined := make(map[sourceKey]string)
+		depDefined := make(map[sourceKey]string)
+		for _, fname := range slices.Sorted(maps.Keys(files)) {
+			content := files[fname]
+			file := path.Join(l.Name, fname)
+			if ext, ok := isComponentsSource(fname); ok {
+				if other, ok := defined[sourceKey{family: componentsSource}]; ok {
+					report.add(file, "", "ambiguous source file, also defined as %s", other)
+					continue
+				}
+				defined[sourceKey{family: componentsSource}] = fname
 
-	for k, v := range data {
-		if strings.HasSuffix(k, suffix) {
-			if r.Versions == nil {
-				err := protojson.Unmarshal(v, &r)
+				r, err := unmarshalComponentRegistry(content, ext)
 				if err != nil {
-					return nil, status.Errorf(codes.Internal, "failed to unmarshal source file: %v", err)
+					report.add(file, "", "failed to unmarshal source file: %v", err)
+					continue
 				}
-			} else {
-				pr := pbVersion.ProductResponse{}
+				validateComponentRegistry(file, r, report)
+				// products declared in later layers replace the ones of earlier layers
+				maps.Copy(s.components, r)
+				continue
+			}
+
+			key, ext, err := parseSourceName(fname)
+			if err != nil {
+				report.add(file, "", "%v", err)
+				continue
+			}
+
+			seen := defined
+			if isDepSource(ext) {
+				seen = depDefined
+			}
+			if other, ok := seen[key]; ok {
+				report.add(file, "", "ambiguous source file, also defined as %s", other)
+				continue
+			}
+			seen[key] = fname
 
-				err := protojson.Unmarshal(v, &pr)
+			if isDepSource(ext) {
+				dep, err := unmarshalDeps(content, ext)
 				if err != nil {
-					return nil, status.Errorf(codes.Internal, "failed to unmarshal source file: %v", err)
+					report.add(file, "", "failed to unmarshal source file: %v", err)
+					continue
+				}
+				validateDeps(file, dep, report)
+				if s.depFiles[key] == nil {
+					s.depFiles[key] = make(map[string]string)
 				}
+				for component, rules := range dep {
+					for v := range rules {
+						s.depFiles[key][component+"."+v] = file
+					}
+				}
+				s.deps[key] = mergeDeps(s.deps[key], dep)
+				continue
+			}
 
-				r.Versions = append(r.Versions, pr.Versions...)
+			vs, err := unmarshalVersionResponse(content, ext)
+			if err != nil {
+				report.add(file, "", "failed to unmarshal source file: %v", err)
+				continue
 			}
+			validateVersionResponse(file, vs, report)
+			if _, ok := origins[key]; !ok {
+				origins[key] = newSourceOrigin(file)
+			}
+			origins[key].record(file, vs)
+			if base, ok := s.versions[key]; ok {
+				mergeVersionResponse(base, vs)
+				continue
+			}
+			s.versions[key] = vs
 		}
 	}
 
-	return &r, nil
+	for key, vs := range s.versions {
+		validateMergedVersionResponse(origins[key], vs, report)
+		for _, ov := range vs.Versions {
+			// operator and product may be omitted in overlays
+			if ov.Operator == "" {
+				ov.Operator = key.version
+			}
+			if ov.Product == "" {
+				ov.Product = key.product
+			}
+		}
+
+		v, err := semver.NewVersion(key.version)
+		if err != nil {
+			report.add(origins[key].file, "", "invalid operator version: %v", err)
+			continue
+		}
+		pk := sourceKey{family: key.family, product: key.product}
+		s.operators[pk] = append(s.operators[pk], v)
+		for _, ov := range vs.Versions {
+			s.products[key.product] = append(s.products[key.product], productVersion{version: v, ov: ov})
+		}
+	}
+	for _, versions := range s.operators {
+		sort.Sort(sort.Reverse(semver.Collection(versions)))
+	}
+	for _, versions := range s.products {
+		slices.SortStableFunc(versions, func(a, b productVersion) int {
+			return a.version.Compare(b.version)
+		})
+	}
+	for key, dep := range s.deps {
+		s.rules[key] = indexDeps(dep)
+	}
+
+	return s, nil
 }
 
-func operatorProductData(productFamily string, product string, version string) (*pbVersion.VersionResponse, error) {
-	source := fmt.Sprintf("%s.%s.%s.json", productFamily, version, product)
-	v, ok := data[source]
-	if !ok {
-		return nil, status.Errorf(codes.NotFound, "no such source file: %s", source)
+// productVersion is an operator version of a product with its parsed version.
+type productVersion struct {
+	version *semver.Version
+	ov      *pbVersion.OperatorVersion
+}
+
+// operatorData returns a page of operator versions of the product sorted in ascending order.
+// Operator versions can be filtered by a semver constraint.
+// Page size 0 returns all versions starting from pageToken. Versions from different
+// source files with the same operator version are never split between pages.
+func (s *sources) operatorData(product string, constraint string, pageSize int, pageToken string, em *embargo) (*pbVersion.ProductResponse, error) {
+	var c *semver.Constraints
+	if constraint != "" {
+		var err error
+		c, err = semver.NewConstraint(constraint)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid operator version constraint: %s", constraint)
+		}
 	}
 
-	data := &pbVersion.VersionResponse{}
-	err := protojson.Unmarshal(v, data)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to unmarshal source file: %v", err)
+	var after *semver.Version
+	if pageToken != "" {
+		t, err := base64.RawURLEncoding.DecodeString(pageToken)
+		if err == nil {
+			after, err = semver.NewVersion(string(t))
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token: %s", pageToken)
+		}
+	}
+
+	r := &pbVersion.ProductResponse{}
+	var last *semver.Version
+	for _, v := range s.products[product] {
+		if after != nil && !v.version.GreaterThan(after) {
+			continue
+		}
+		if (c != nil && !c.Check(v.version)) || em.hides(v.ov.PublishAt) {
+			continue
+		}
+		if pageSize > 0 && len(r.Versions) >= pageSize && !v.version.Equal(last) {
+			// the token is the last returned version, so pages stay consistent across reloads
+			r.NextPageToken = base64.RawURLEncoding.EncodeToString([]byte(last.Original()))
+			break
+		}
+
+		r.Versions = append(r.Versions, proto.Clone(v.ov).(*pbVersion.OperatorVersion))
+		last = v.version
+	}
+	r.Versions = em.filter(r.Versions)
+
+	return r, nil
+}
+
+// resolveOperatorVersion resolves the operator version requested as latest, major.minor
+// or a semver constraint to the highest matching version which has a source file.
+// Exact versions are returned as is. Embargoed versions are never returned.
+func (s *sources) resolveOperatorVersion(productFamily string, product string, version string, em *embargo) (string, error) {
+	if vs, ok := s.versions[sourceKey{family: productFamily, product: product, version: version}]; ok && !em.hidesSource(vs) {
+		return version, nil
+	}
+
+	var match func(v *semver.Version) bool
+	switch {
+	case strings.ToLower(version) == latest:
+		match = func(*semver.Version) bool { return true }
+	case majorMinorOnlyRegexp.MatchString(version):
+		mm, err := semver.NewVersion(version)
+		if err != nil {
+			return "", status.Errorf(codes.InvalidArgument, "invalid operator version: %s", version)
+		}
+		match = func(v *semver.Version) bool { return v.Major() == mm.Major() && v.Minor() == mm.Minor() }
+	default:
+		c, err := semver.NewConstraint(version)
+		if err != nil {
+			key := sourceKey{family: productFamily, product: product, version: version}
+			return "", status.Errorf(codes.NotFound, "no such source file: %s", key.fileName(sourceExt))
+		}
+		match = c.Check
 	}
 
-	return data, nil
+	for _, v := range s.operators[sourceKey{family: productFamily, product: product}] {
+		if match(v) && !em.hidesSource(s.versions[sourceKey{family: productFamily, product: product, version: v.Original()}]) {
+			return v.Original(), nil
+		}
+	}
+	return "", status.Errorf(codes.NotFound, "no %s version matches %s", product, version)
 }
 
-func getDep(product string, operatorVersion string) (Deps, error) {
-	source := fmt.Sprintf("operator.%s.%s.dep.json", operatorVersion, product)
-	v, ok := deps[source]
+// operatorProductData returns a copy of the source for the given operator version,
+// so it can be modified by filters.
+func (s *sources) operatorProductData(productFamily string, product string, version string) (*pbVersion.VersionResponse, error) {
+	key := sourceKey{family: productFamily, product: product, version: version}
+	v, ok := s.versions[key]
 	if !ok {
-		return Deps{}, status.Errorf(codes.NotFound, "no such source file: %s", source)
+		return nil, status.Errorf(codes.NotFound, "no such source file: %s", key.fileName(sourceExt))
 	}
 
-	dep := Deps{}
-	err := json.Unmarshal(v, &dep)
-	if err != nil {
-		return Deps{}, status.Errorf(codes.Internal, "failed to unmarshal source file: %v", err)
+	return proto.Clone(v).(*pbVersion.VersionResponse), nil
+}
+
+// getDep returns the indexed dependency rules for the given operator version.
+// The rules are shared between requests and must not be modified.
+func (s *sources) getDep(product string, operatorVersion string) (indexedDeps, error) {
+	key := sourceKey{family: "operator", product: product, version: operatorVersion}
+	dep, ok := s.rules[key]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no such source file: %s", key.fileName(depSourceExt))
 	}
+
 	return dep, nil
 }
+
+// apply returns the source of the requested operator version with a single version of every component
+// selected for the requested database version. It resolves req.OperatorVersion to an exact version.
+func (s *sources) apply(req *pbVersion.ApplyRequest, em *embargo) (*pbVersion.VersionResponse, error) {
+	err := transformRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	req.OperatorVersion, err = s.resolveOperatorVersion("operator", req.Product, req.OperatorVersion, em)
+	if err != nil {
+		return nil, err
+	}
+
+	vs, err := s.operatorProductData("operator", req.Product, req.OperatorVersion)
+	if err != nil {
+		return nil, err
+	}
+	database := s.components[req.Product].Database
+	if len(vs.Versions) > 0 && em.hidesComponent(vs.Versions[0].Matrix, database) {
+		return nil, status.Errorf(codes.NotFound, "no published %s versions for operator version %s", database, req.OperatorVersion)
+	}
+	vs.Versions = em.filter(vs.Versions)
+
+	var e *explanation
+	if req.Explain && len(vs.Versions) > 0 {
+		e = newExplanation(vs.Versions[0].Matrix)
+	}
+
+	dist, err := newDistribution(req.Distribution)
+	if err != nil {
+		return nil, err
+	}
+	// digests of distribution images are checked for the architecture
+	dist.selectImages(vs)
+	newRollout(req, time.Now()).apply(vs, e)
+	withdrawn := newWithdrawals(req, s.components[req.Product])
+	if err := withdrawn.filter(vs, e); err != nil {
+		return nil, err
+	}
+	// clusters running a withdrawn database version upgrade from the version they are routed to
+	req.DatabaseVersion = withdrawn.current
+
+	compat, err := newCompatibility(req.KubeVersion, req.Platform, req.Arch)
+	if err != nil {
+		return nil, err
+	}
+	// the database and the components running or enabled in the cluster need a compatible version,
+	// the others are left out with a warning
+	required := []string{database}
+	for component := range pinnedVersions(req) {
+		required = append(required, component)
+	}
+	if req.BackupsEnabled {
+		required = append(required, "backup")
+	}
+	if req.PmmEnabled {
+		required = append(required, "pmm")
+	}
+	if err := compat.filter(vs, e, required...); err != nil {
+		return nil, err
+	}
+
+	deps, err := s.getDep(req.Product, req.OperatorVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.components.apply(vs, deps, req, e); err != nil {
+		return nil, err
+	}
+	if err := dist.check(vs.Versions[0].Matrix); err != nil {
+		return nil, err
+	}
+	warnings, err := withdrawn.check(vs.Versions[0].Matrix)
+	if err != nil {
+		return nil, err
+	}
+	vs.Warnings = append(vs.Warnings, warnings...)
+	if e != nil {
+		vs.Explanation = e.result(vs.Versions[0].Matrix)
+	}
+
+	return vs, nil
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+)
+
+// SourceStore provides operator source and dependency files.
+type SourceStore interface {
+	// ReadAll returns the content of all source files keyed by file name.
+	ReadAll() (map[string][]byte, error)
+}
+
+type fsSourceStore struct {
+	fs  fs.FS
+	dir string
+}
+
+// NewDirSourceStore returns a SourceStore that reads source files from a directory on disk.
+// A missing directory is treated as an empty one.
+func NewDirSourceStore(dir string) SourceStore {
+	return &fsSourceStore{fs: os.DirFS(dir), dir: "."}
+}
+
+// NewEmbedSourceStore returns a SourceStore that reads source files from dir of an embedded filesystem.
+func NewEmbedSourceStore(efs embed.FS, dir string) SourceStore {
+	return &fsSourceStore{fs: efs, dir: dir}
+}
+
+func (s *fsSourceStore) ReadAll() (map[string][]byte, error) {
+	files, err := fs.ReadDir(s.fs, s.dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return map[string][]byte{}, nil
+		}
+		return nil, err
+	}
+
+	res := make(map[string][]byte, len(files))
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+
+		fname := file.Name()
+		content, err := fs.ReadFile(s.fs, path.Join(s.dir, fname))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read source file %s: %w", fname, err)
+		}
+		res[fname] = content
+	}
+
+	return res, nil
+}
+
+// MemorySourceStore is a SourceStore that keeps source files in memory.
+// Keys are file names, such as operator.1.19.1.psmdb-operator.json.
+type MemorySourceStore map[string][]byte
+
+func (s MemorySourceStore) ReadAll() (map[string][]byte, error) {
+	return maps.Clone(s), nil
+}
@@ -0,0 +1,326 @@
+package server
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+)
+
+func TestParseSourceName(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		name    string
+		key     sourceKey
+		ext     string
+		wantErr bool
+	}{
+		"operator source": {
+			name: "operator.1.19.1.psmdb-operator.json",
+			key:  sourceKey{family: "operator", product: "psmdb-operator", version: "1.19.1"},
+			ext:  sourceExt,
+		},
+		"dependency source": {
+			name: "operator.2.5.0.pg-operator.dep.json",
+			key:  sourceKey{family: "operator", product: "pg-operator", version: "2.5.0"},
+			ext:  depSourceExt,
+		},
+		"pmm source": {
+			name: "pmm.2.29.0.pmm-server.json",
+			key:  sourceKey{family: "pmm", product: "pmm-server", version: "2.29.0"},
+			ext:  sourceExt,
+		},
+		"yaml source": {
+			name: "operator.1.19.1.psmdb-operator.yaml",
+			key:  sourceKey{family: "operator", product: "psmdb-operator", version: "1.19.1"},
+			ext:  ".yaml",
+		},
+		"yaml dependency source": {
+			name: "operator.2.5.0.pg-operator.dep.yml",
+			key:  sourceKey{family: "operator", product: "pg-operator", version: "2.5.0"},
+			ext:  ".dep.yml",
+		},
+		"unsupported extension": {
+			name:    "operator.1.19.1.psmdb-operator.txt",
+			wantErr: true,
+		},
+		"missing version": {
+			name:    "operator.psmdb-operator.json",
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			key, ext, err := parseSourceName(tt.name)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.key, key)
+			assert.Equal(t, tt.ext, ext)
+		})
+	}
+}
+
+func TestSources_operatorProductDataReturnsCopy(t *testing.T) {
+	t.Parallel()
+
+	report := &ValidationReport{}
+	s, err := readSources([]Layer{{Sources: testSources}}, report)
+	require.NoError(t, err)
+	require.NoError(t, report.err())
+
+	vs, err := s.operatorProductData("operator", "psmdb-operator", "1.0.0")
+	require.NoError(t, err)
+	require.NoError(t, deleteOtherBut("8.0.4-1", vs.Versions[0].Matrix.Mongod))
+
+	vs, err = s.operatorProductData("operator", "psmdb-operator", "1.0.0")
+	require.NoError(t, err)
+	assert.Len(t, vs.Versions[0].Matrix.Mongod, 3)
+}
+
+func TestReadSources_yaml(t *testing.T) {
+	t.Parallel()
+
+	yamlSources := MemorySourceStore{
+		"operator.1.0.0.psmdb-operator.yaml": []byte(`versions:
+  - operator: 1.0.0
+    product: psmdb-operator
+    matrix:
+      mongod:
+        7.0.14-8:
+          image_path: percona/percona-server-mongodb:7.0.14-8
+          status: available
+        7.0.15-9:
+          image_path: percona/percona-server-mongodb:7.0.15-9
+          status: recommended
+        8.0.4-1:
+          image_path: percona/percona-server-mongodb:8.0.4-1
+          status: available
+      backup:
+        2.7.0:
+          image_path: percona/percona-backup-mongodb:2.7.0
+          status: recommended
+        2.8.0:
+          image_path: percona/percona-backup-mongodb:2.8.0
+          status: recommended
+      pmm:
+        2.44.0:
+          image_path: percona/pmm-client:2.44.0
+          status: recommended
+        3.1.0:
+          image_path: percona/pmm-client:3.1.0
+          status: recommended
+      operator:
+        1.0.0:
+          image_path: percona/percona-server-mongodb-operator:1.0.0
+          status: recommended
+`),
+		"operator.1.0.0.psmdb-operator.dep.yml": []byte(`backup:
+  2.8.0:
+    ">=": [{var: productVersion}, "8.0"]
+  2.7.0:
+    "<":
+      - var: productVersion
+      - "8.0"
+`),
+	}
+
+	report := &ValidationReport{}
+	fromJSON, err := readSources([]Layer{{Sources: testSources}}, report)
+	require.NoError(t, err)
+	fromYAML, err := readSources([]Layer{{Sources: yamlSources}}, report)
+	require.NoError(t, err)
+	require.NoError(t, report.err())
+
+	key := sourceKey{family: "operator", product: "psmdb-operator", version: "1.0.0"}
+	assert.True(t, proto.Equal(fromJSON.versions[key], fromYAML.versions[key]))
+	assert.Equal(t, fromJSON.deps[key], fromYAML.deps[key])
+
+	t.Run("unquoted versions", func(t *testing.T) {
+		t.Parallel()
+
+		fromYAML, err := unmarshalDeps([]byte(`postgresql:
+  16.10:
+    ">=": [{var: productVersion}, 16.10]
+  17:
+    and:
+      - ">=": [{var: productVersion}, 17]
+      - "<": [{var: productVersion}, 8.0]
+      - "!": false
+`), ".dep.yaml")
+		require.NoError(t, err)
+		fromJSON, err := unmarshalDeps([]byte(`{
+  "postgresql": {
+    "16.10": {">=": [{"var": "productVersion"}, "16.10"]},
+    "17": {"and": [
+      {">=": [{"var": "productVersion"}, "17"]},
+      {"<": [{"var": "productVersion"}, "8.0"]},
+      {"!": false}
+    ]}
+  }
+}`), ".dep.json")
+		require.NoError(t, err)
+		assert.Equal(t, fromJSON, fromYAML)
+
+		_, err = unmarshalDeps([]byte("postgresql:\n  [16]: true\n"), ".dep.yaml")
+		assert.Error(t, err)
+	})
+
+	t.Run("ambiguous formats", func(t *testing.T) {
+		t.Parallel()
+
+		both := maps.Clone(testSources)
+		maps.Copy(both, yamlSources)
+
+		report := &ValidationReport{}
+		_, err := readSources([]Layer{{Sources: both}}, report)
+		require.NoError(t, err)
+		require.Error(t, report.err())
+
+		files := make([]string, 0, len(report.Issues))
+		for _, i := range report.Issues {
+			files = append(files, i.File)
+		}
+		assert.ElementsMatch(t, []string{"operator.1.0.0.psmdb-operator.yaml", "operator.1.0.0.psmdb-operator.dep.yml"}, files)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		invalid := MemorySourceStore{
+			"operator.1.0.0.psmdb-operator.yaml": []byte(`versions:
+  - matrix:
+      mongod:
+        8.0.4-1:
+          image_path: percona/percona-server-mongodb:8.0.4-1
+`),
+			"operator.1.0.0.psmdb-operator.dep.yml": []byte(`backup:
+  2.8.0:
+    unknown: [{var: productVersion}, "8.0"]
+`),
+		}
+
+		report := &ValidationReport{}
+		_, err := readSources([]Layer{{Name: "yaml", Sources: invalid}}, report)
+		require.NoError(t, err)
+		require.Error(t, report.err())
+
+		type issue struct{ file, key string }
+		got := make([]issue, 0, len(report.Issues))
+		for _, i := range report.Issues {
+			got = append(got, issue{i.File, i.Key})
+		}
+		assert.Equal(t, []issue{
+			{"yaml/operator.1.0.0.psmdb-operator.dep.yml", "backup.2.8.0"},
+			{"yaml/operator.1.0.0.psmdb-operator.yaml", "matrix.mongod.8.0.4-1"},
+		}, got)
+	})
+}
+
+func TestSources_resolveOperatorVersion(t *testing.T) {
+	t.Parallel()
+
+	store := MemorySourceStore{}
+	for _, v := range []string{"1.18.0", "1.19.0", "1.19.1", "1.20.0"} {
+		store["operator."+v+".psmdb-operator.json"] = []byte(`{"versions": [{"matrix": {}}]}`)
+	}
+	report := &ValidationReport{}
+	s, err := readSources([]Layer{{Sources: store}}, report)
+	require.NoError(t, err)
+	require.NoError(t, report.err())
+
+	tests := map[string]struct {
+		version string
+		want    string
+		code    codes.Code
+	}{
+		"exact":              {version: "1.19.0", want: "1.19.0"},
+		"latest":             {version: "latest", want: "1.20.0"},
+		"major.minor":        {version: "1.19", want: "1.19.1"},
+		"constraint":         {version: "<1.20.0", want: "1.19.1"},
+		"range":              {version: ">=1.18.0, <1.19.1", want: "1.19.0"},
+		"unknown exact":      {version: "1.19.2", code: codes.NotFound},
+		"unknown minor":      {version: "1.21", code: codes.NotFound},
+		"invalid constraint": {version: "foo", code: codes.NotFound},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := s.resolveOperatorVersion("operator", "psmdb-operator", tt.version, nil)
+			if tt.code != codes.OK {
+				assert.Equal(t, tt.code, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSources_operatorData(t *testing.T) {
+	t.Parallel()
+
+	store := MemorySourceStore{}
+	for _, v := range []string{"2.10.0", "2.4.1", "2.5.0", "2.9.0", "3.0.0"} {
+		store["operator."+v+".pg-operator.json"] = []byte(`{"versions": [{"matrix": {}}]}`)
+	}
+	report := &ValidationReport{}
+	s, err := readSources([]Layer{{Sources: store}}, report)
+	require.NoError(t, err)
+	require.NoError(t, report.err())
+
+	operators := func(resp *pbVersion.ProductResponse) []string {
+		res := make([]string, 0, len(resp.Versions))
+		for _, v := range resp.Versions {
+			res = append(res, v.Operator)
+		}
+		return res
+	}
+
+	t.Run("sorted by semver", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := s.operatorData("pg-operator", "", 0, "", nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2.4.1", "2.5.0", "2.9.0", "2.10.0", "3.0.0"}, operators(resp))
+		assert.Empty(t, resp.NextPageToken)
+	})
+
+	t.Run("filtered and paginated", func(t *testing.T) {
+		t.Parallel()
+
+		var pages [][]string
+		token := ""
+		for {
+			resp, err := s.operatorData("pg-operator", ">=2.5.0, <3.0.0", 2, token, nil)
+			require.NoError(t, err)
+			pages = append(pages, operators(resp))
+			if resp.NextPageToken == "" {
+				break
+			}
+			token = resp.NextPageToken
+		}
+		assert.Equal(t, [][]string{{"2.5.0", "2.9.0"}, {"2.10.0"}}, pages)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := s.operatorData("pg-operator", "foo", 0, "", nil)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		_, err = s.operatorData("pg-operator", "", 1, "!", nil)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
@@ -0,0 +1,314 @@
+package server
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/Masterminds/semver"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+)
+
+// versionLineRegexp matches version lines, e.g. 8 or 8.4.
+var versionLineRegexp = regexp.MustCompile(`^\d+(\.\d+)?$`)
+
+// planUpgrade plans the upgrade from the running operator and database versions to the target ones.
+// The operator is upgraded first, one minor version at a time, keeping the database version if
+// the operator supports it. Then the database is upgraded one version line at a time, where
+// a line is a major.minor version or a major version for products selected within a major,
+// following the declared upgrade edges of the product.
+func (s *sources) planUpgrade(req *pbVersion.PlanUpgradeRequest, em *embargo) ([]*pbVersion.UpgradeHop, error) {
+	pc, ok := s.components[req.Product]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid product: %s", req.Product)
+	}
+	policy := selectionPolicies[pc.Policy]
+
+	current, err := semver.NewVersion(req.OperatorVersion)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid operator version: %s", req.OperatorVersion)
+	}
+	if _, err := s.databaseVersions(pc, req.Product, req.OperatorVersion, em); err != nil {
+		return nil, err
+	}
+	targetOperator := req.TargetOperatorVersion
+	if targetOperator == "" {
+		targetOperator = req.OperatorVersion
+	}
+	targetOperator, err = s.resolveOperatorVersion("operator", req.Product, targetOperator, em)
+	if err != nil {
+		return nil, err
+	}
+	target, err := semver.NewVersion(targetOperator)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "invalid operator version: %s", targetOperator)
+	}
+	if target.LessThan(current) {
+		return nil, status.Errorf(codes.InvalidArgument, "operator downgrade from %s to %s is not supported", req.OperatorVersion, targetOperator)
+	}
+
+	db := req.DatabaseVersion
+	if _, err := semver.NewVersion(db); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid database version: %s", db)
+	}
+
+	var hops []*pbVersion.UpgradeHop
+	running := req.OperatorVersion
+	for _, op := range s.operatorUpgradePath(req.Product, current, target, em) {
+		versions, err := s.databaseVersions(pc, req.Product, op, em)
+		if err != nil {
+			return nil, err
+		}
+
+		reason := fmt.Sprintf("upgrade operator to %s", op)
+		if op != targetOperator {
+			reason += ", operators are upgraded one minor version at a time"
+		}
+		for {
+			if v, ok := versions[db]; ok && v.Status != pbVersion.Status_disabled {
+				break
+			}
+			if next, ok := policy.lineVersion(versions, db, true); ok {
+				reason += fmt.Sprintf(" and %s to %s as %s is not supported by it", pc.Database, next, db)
+				db = next
+				break
+			}
+
+			// the operator does not support the database line anymore, upgrade the database first
+			runningVersions, err := s.databaseVersions(pc, req.Product, running, em)
+			if err != nil {
+				return nil, err
+			}
+			next, ok := pc.nextLineVersion(runningVersions, db)
+			if !ok {
+				return nil, status.Errorf(codes.FailedPrecondition, "operator %s does not support %s %s or a newer version of its line", op, pc.Database, db)
+			}
+			hop, err := s.upgradeHop(req.Product, running, next, fmt.Sprintf("upgrade %s to %s as operator %s does not support %s", pc.Database, next, op, db), em)
+			if err != nil {
+				return nil, err
+			}
+			hops = append(hops, hop)
+			db = next
+		}
+
+		hop, err := s.upgradeHop(req.Product, op, db, reason, em)
+		if err != nil {
+			return nil, err
+		}
+		hops = append(hops, hop)
+		running = op
+	}
+
+	if req.TargetDatabaseVersion == "" {
+		return hops, nil
+	}
+
+	versions, err := s.databaseVersions(pc, req.Product, targetOperator, em)
+	if err != nil {
+		return nil, err
+	}
+	path, err := pc.databaseUpgradePath(versions, db, req.TargetDatabaseVersion)
+	if err != nil {
+		return nil, err
+	}
+	for i, v := range path {
+		reason := fmt.Sprintf("upgrade %s to %s", pc.Database, v)
+		if i < len(path)-1 {
+			reason += fmt.Sprintf(", the recommended version of its line, %s is upgraded one version line at a time", pc.Database)
+		}
+		hop, err := s.upgradeHop(req.Product, targetOperator, v, reason, em)
+		if err != nil {
+			return nil, err
+		}
+		hops = append(hops, hop)
+	}
+
+	return hops, nil
+}
+
+// operatorUpgradePath returns operator versions newer than current up to target in ascending order:
+// the highest patch of every newer minor version in between and the target itself. Embargoed versions are skipped.
+func (s *sources) operatorUpgradePath(product string, current, target *semver.Version, em *embargo) []string {
+	var path []*semver.Version
+	// operators are sorted in descending order
+	for _, v := range slices.Backward(s.operators[sourceKey{family: "operator", product: product}]) {
+		if !v.GreaterThan(current) || v.GreaterThan(target) {
+			continue
+		}
+		if em.hidesSource(s.versions[sourceKey{family: "operator", product: product, version: v.Original()}]) {
+			continue
+		}
+		// patch releases of the current minor version are skipped unless they are the target
+		if v.Major() == current.Major() && v.Minor() == current.Minor() && !v.Equal(target) {
+			continue
+		}
+		if n := len(path); n > 0 && path[n-1].Major() == v.Major() && path[n-1].Minor() == v.Minor() {
+			path = path[:n-1]
+		}
+		path = append(path, v)
+	}
+
+	res := make([]string, 0, len(path))
+	for _, v := range path {
+		res = append(res, v.Original())
+	}
+	return res
+}
+
+// databaseVersions returns versions of the database of the given operator version without the embargoed ones.
+// The versions are shared with the sources and must not be modified.
+func (s *sources) databaseVersions(pc ProductComponents, product string, operatorVersion string, em *embargo) (map[string]*pbVersion.Version, error) {
+	key := sourceKey{family: "operator", product: product, version: operatorVersion}
+	vs, ok := s.versions[key]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no such source file: %s", key.fileName(sourceExt))
+	}
+	if len(vs.Versions) == 0 || vs.Versions[0].Matrix == nil {
+		return nil, nil
+	}
+	return em.filterVersions(componentVersions(vs.Versions[0].Matrix, pc.Database)), nil
+}
+
+// upgradeHop returns a hop to the given operator and database versions with the versions of all components.
+func (s *sources) upgradeHop(product, operatorVersion, databaseVersion, reason string, em *embargo) (*pbVersion.UpgradeHop, error) {
+	vs, err := s.apply(&pbVersion.ApplyRequest{
+		Product:         product,
+		OperatorVersion: operatorVersion,
+		Apply:           databaseVersion,
+	}, em)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pbVersion.UpgradeHop{
+		OperatorVersion: operatorVersion,
+		DatabaseVersion: databaseVersion,
+		Matrix:          vs.Versions[0].Matrix,
+		Reason:          reason,
+	}, nil
+}
+
+// sameLine reports whether versions a and b belong to the same version line.
+func (p selectionPolicy) sameLine(a, b *semver.Version) bool {
+	return a.Major() == b.Major() && (p.sameMajor || a.Minor() == b.Minor())
+}
+
+// lineVersion returns the highest recommended version of the line of v or, if there is none, the highest
+// non-disabled one. Withdrawn versions are skipped. With notLower only versions not lower than v are considered.
+func (p selectionPolicy) lineVersion(versions map[string]*pbVersion.Version, v string, notLower bool) (string, bool) {
+	lv, err := semver.NewVersion(v)
+	if err != nil {
+		return "", false
+	}
+
+	var best *semver.Version
+	bestRecommended := false
+	for k, ver := range versions {
+		sv, err := semver.NewVersion(k)
+		if err != nil || ver.Status == pbVersion.Status_disabled || ver.Withdrawn != nil || !p.sameLine(sv, lv) || (notLower && sv.LessThan(lv)) {
+			continue
+		}
+		rec := ver.Status == pbVersion.Status_recommended
+		if best == nil || (rec && !bestRecommended) || (rec == bestRecommended && sv.GreaterThan(best)) {
+			best, bestRecommended = sv, rec
+		}
+	}
+	if best == nil {
+		return "", false
+	}
+	return best.Original(), true
+}
+
+// databaseUpgradePath returns database versions to upgrade from current to target in ascending order:
+// the recommended version of every line in between and the target. Target is either an exact
+// version or a version line, e.g. 8.4, which resolves to the recommended version of the line.
+func (pc ProductComponents) databaseUpgradePath(versions map[string]*pbVersion.Version, current, target string) ([]string, error) {
+	p := selectionPolicies[pc.Policy]
+	if v, ok := versions[target]; ok && v.Status == pbVersion.Status_disabled {
+		return nil, status.Errorf(codes.FailedPrecondition, "database version %s is disabled", target)
+	} else if !ok {
+		if !versionLineRegexp.MatchString(target) {
+			return nil, status.Errorf(codes.NotFound, "database version %s does not exist", target)
+		}
+		t, ok := p.lineVersion(versions, target, false)
+		if !ok {
+			return nil, status.Errorf(codes.NotFound, "no database version of line %s", target)
+		}
+		target = t
+	}
+
+	c, err := semver.NewVersion(current)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid database version: %s", current)
+	}
+	t, err := semver.NewVersion(target)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "invalid database version: %s", target)
+	}
+	switch {
+	case t.Equal(c):
+		return nil, nil
+	case t.LessThan(c):
+		return nil, status.Errorf(codes.InvalidArgument, "database downgrade from %s to %s is not supported", current, target)
+	case p.sameLine(c, t):
+		return []string{target}, nil
+	}
+
+	var path []string
+	for v := current; ; {
+		next, ok := pc.nextLineVersion(versions, v)
+		nv, _ := semver.NewVersion(next)
+		if !ok || nv.GreaterThan(t) && !p.sameLine(nv, t) {
+			return nil, status.Errorf(codes.FailedPrecondition, "no upgrade path from %s to %s", v, target)
+		}
+		if p.sameLine(nv, t) {
+			break
+		}
+		path = append(path, next)
+		v = next
+	}
+	return append(path, target), nil
+}
+
+// nextLineVersion returns the version lineVersion selects for the first line with versions
+// the line of v can be upgraded to according to the declared upgrade edges. Products
+// without declared edges are upgraded to the lowest newer line.
+func (pc ProductComponents) nextLineVersion(versions map[string]*pbVersion.Version, v string) (string, bool) {
+	p := selectionPolicies[pc.Policy]
+	if len(pc.Upgrades) == 0 {
+		return p.nextLineVersion(versions, v)
+	}
+
+	for _, line := range pc.upgradeLines(v) {
+		if lv, ok := p.lineVersion(versions, line, false); ok {
+			return lv, true
+		}
+	}
+	return "", false
+}
+
+// nextLineVersion returns the version lineVersion selects for the lowest version line newer than the line of v.
+func (p selectionPolicy) nextLineVersion(versions map[string]*pbVersion.Version, v string) (string, bool) {
+	cv, err := semver.NewVersion(v)
+	if err != nil {
+		return "", false
+	}
+
+	var next *semver.Version
+	for k, ver := range versions {
+		sv, err := semver.NewVersion(k)
+		if err != nil || ver.Status == pbVersion.Status_disabled || ver.Withdrawn != nil || !sv.GreaterThan(cv) || p.sameLine(sv, cv) {
+			continue
+		}
+		if next == nil || sv.LessThan(next) {
+			next = sv
+		}
+	}
+	if next == nil {
+		return "", false
+	}
+	return p.lineVersion(versions, next.Original(), false)
+}
@@ -0,0 +1,301 @@
+package server
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+)
+
+var upgradeTestSources = MemorySourceStore{
+	"components.json": []byte(`{
+  "pxc-operator": {
+    "database": "pxc",
+    "policy": "same-minor-next-recommended",
+    "components": [{"name": "backup", "selector": "dep"}]
+  }
+}`),
+	"operator.1.14.0.pxc-operator.json": []byte(`{"versions": [{"matrix": {
+  "pxc": {
+    "5.7.44-31.65": {"status": "recommended"},
+    "8.0.35-27.1": {"status": "recommended"}
+  }
+}}]}`),
+	"operator.1.15.0.pxc-operator.json": []byte(`{"versions": [{"matrix": {
+  "pxc": {
+    "5.7.44-31.65": {"status": "recommended"},
+    "8.0.36-28.1": {"status": "recommended"}
+  }
+}}]}`),
+	"operator.1.15.1.pxc-operator.json": []byte(`{"versions": [{"matrix": {
+  "pxc": {
+    "5.7.44-31.65": {"status": "recommended"},
+    "8.0.36-28.1": {"status": "recommended"},
+    "8.0.37-29.1": {"status": "available"}
+  }
+}}]}`),
+	"operator.1.16.0.pxc-operator.json": []byte(`{"versions": [{"matrix": {
+  "pxc": {
+    "5.7.44-31.65": {"status": "recommended"},
+    "8.0.36-28.1": {"status": "recommended"},
+    "8.0.39-30.1": {"status": "recommended"},
+    "8.4.2-2.1": {"status": "available"},
+    "8.4.3-3.1": {"status": "recommended"},
+    "8.4.4-4.1": {"status": "disabled"}
+  },
+  "backup": {
+    "2.4.1": {"image_path": "percona/percona-xtrabackup:2.4.1", "status": "recommended"},
+    "8.0.35-31": {"image_path": "percona/percona-xtrabackup:8.0.35-31", "status": "recommended"},
+    "8.4.0-1": {"image_path": "percona/percona-xtrabackup:8.4.0-1", "status": "recommended"}
+  }
+}}]}`),
+	"operator.1.17.0.pxc-operator.json": []byte(`{"versions": [{"matrix": {
+  "pxc": {
+    "8.0.39-30.1": {"status": "recommended"},
+    "8.4.3-3.1": {"status": "recommended"}
+  }
+}}]}`),
+	"operator.1.14.0.pxc-operator.dep.json": []byte(`{}`),
+	"operator.1.15.0.pxc-operator.dep.json": []byte(`{}`),
+	"operator.1.15.1.pxc-operator.dep.json": []byte(`{}`),
+	"operator.1.17.0.pxc-operator.dep.json": []byte(`{}`),
+	"operator.1.16.0.pxc-operator.dep.json": []byte(`{
+  "backup": {
+    "8.4.0-1": {">=": [{"var": "productVersion"}, "8.4"]},
+    "8.0.35-31": {"and": [{">=": [{"var": "productVersion"}, "8.0"]}, {"<": [{"var": "productVersion"}, "8.4"]}]},
+    "2.4.1": {"<": [{"var": "productVersion"}, "8.0"]}
+  }
+}`),
+}
+
+func TestBackend_PlanUpgrade(t *testing.T) {
+	t.Parallel()
+
+	b, err := New(upgradeTestSources, fstest.MapFS{}, fstest.MapFS{})
+	require.NoError(t, err)
+
+	type hop struct{ operator, database, backup string }
+	tests := map[string]struct {
+		req  *pbVersion.PlanUpgradeRequest
+		want []hop
+		code codes.Code
+	}{
+		"operator one minor at a time, then database": {
+			req: &pbVersion.PlanUpgradeRequest{
+				OperatorVersion:       "1.14.0",
+				DatabaseVersion:       "8.0.35-27.1",
+				TargetOperatorVersion: "1.16",
+				TargetDatabaseVersion: "8.4",
+			},
+			want: []hop{
+				{"1.15.1", "8.0.36-28.1", ""},
+				{"1.16.0", "8.0.36-28.1", "8.0.35-31"},
+				{"1.16.0", "8.4.3-3.1", "8.4.0-1"},
+			},
+		},
+		"database one line at a time": {
+			req: &pbVersion.PlanUpgradeRequest{
+				OperatorVersion:       "1.16.0",
+				DatabaseVersion:       "5.7.44-31.65",
+				TargetDatabaseVersion: "8.4.3-3.1",
+			},
+			want: []hop{
+				{"1.16.0", "8.0.39-30.1", "8.0.35-31"},
+				{"1.16.0", "8.4.3-3.1", "8.4.0-1"},
+			},
+		},
+		"within database line": {
+			req: &pbVersion.PlanUpgradeRequest{
+				OperatorVersion:       "1.16.0",
+				DatabaseVersion:       "8.4.2-2.1",
+				TargetDatabaseVersion: "8.4",
+			},
+			want: []hop{{"1.16.0", "8.4.3-3.1", "8.4.0-1"}},
+		},
+		"database upgraded before operator drops its line": {
+			req: &pbVersion.PlanUpgradeRequest{
+				OperatorVersion:       "1.16.0",
+				DatabaseVersion:       "5.7.44-31.65",
+				TargetOperatorVersion: "latest",
+			},
+			want: []hop{
+				{"1.16.0", "8.0.39-30.1", "8.0.35-31"},
+				{"1.17.0", "8.0.39-30.1", ""},
+			},
+		},
+		"patch of current operator minor": {
+			req: &pbVersion.PlanUpgradeRequest{
+				OperatorVersion:       "1.15.0",
+				DatabaseVersion:       "5.7.44-31.65",
+				TargetOperatorVersion: "1.16",
+			},
+			want: []hop{{"1.16.0", "5.7.44-31.65", "2.4.1"}},
+		},
+		"target reached": {
+			req: &pbVersion.PlanUpgradeRequest{
+				OperatorVersion:       "1.16.0",
+				DatabaseVersion:       "8.4.3-3.1",
+				TargetDatabaseVersion: "8.4",
+			},
+		},
+		"operator downgrade": {
+			req: &pbVersion.PlanUpgradeRequest{
+				OperatorVersion:       "1.15.0",
+				DatabaseVersion:       "8.0.36-28.1",
+				TargetOperatorVersion: "1.14.0",
+			},
+			code: codes.InvalidArgument,
+		},
+		"database downgrade": {
+			req: &pbVersion.PlanUpgradeRequest{
+				OperatorVersion:       "1.16.0",
+				DatabaseVersion:       "8.4.3-3.1",
+				TargetDatabaseVersion: "8.0",
+			},
+			code: codes.InvalidArgument,
+		},
+		"disabled target database version": {
+			req: &pbVersion.PlanUpgradeRequest{
+				OperatorVersion:       "1.16.0",
+				DatabaseVersion:       "8.4.3-3.1",
+				TargetDatabaseVersion: "8.4.4-4.1",
+			},
+			code: codes.FailedPrecondition,
+		},
+		"unknown target database version": {
+			req: &pbVersion.PlanUpgradeRequest{
+				OperatorVersion:       "1.16.0",
+				DatabaseVersion:       "8.4.3-3.1",
+				TargetDatabaseVersion: "8.4.9-9.1",
+			},
+			code: codes.NotFound,
+		},
+		"database not supported by intermediate operator": {
+			req: &pbVersion.PlanUpgradeRequest{
+				OperatorVersion:       "1.14.0",
+				DatabaseVersion:       "8.0.35-27.1",
+				TargetOperatorVersion: "1.15.0",
+				TargetDatabaseVersion: "8.0.36-28.1",
+			},
+			want: []hop{{"1.15.0", "8.0.36-28.1", ""}},
+		},
+		"unknown current operator version": {
+			req: &pbVersion.PlanUpgradeRequest{
+				OperatorVersion: "1.13.0",
+				DatabaseVersion: "8.0.35-27.1",
+			},
+			code: codes.NotFound,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.req.Product = "pxc-operator"
+			resp, err := b.PlanUpgrade(context.Background(), tt.req)
+			if tt.code != codes.OK {
+				assert.Equal(t, tt.code, status.Code(err), err)
+				return
+			}
+			require.NoError(t, err)
+
+			got := make([]hop, 0, len(resp.Hops))
+			for _, h := range resp.Hops {
+				assert.NotEmpty(t, h.Reason)
+				assert.Equal(t, []string{h.DatabaseVersion}, slices.Collect(maps.Keys(h.Matrix.Pxc)))
+				backup := ""
+				for k := range h.Matrix.Backup {
+					backup = k
+				}
+				got = append(got, hop{h.OperatorVersion, h.DatabaseVersion, backup})
+			}
+			if tt.want == nil {
+				tt.want = []hop{}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBackend_upgradeEdges(t *testing.T) {
+	t.Parallel()
+
+	// 5.7 is upgraded straight to 8.4, there is no edge from 8.4
+	overlay := Layer{Sources: MemorySourceStore{"components.json": []byte(`{
+  "pxc-operator": {
+    "database": "pxc",
+    "policy": "same-minor-next-recommended",
+    "components": [{"name": "backup", "selector": "dep"}],
+    "upgrades": {"5.7": ["9.0", "8.4"], "8.0": ["8.4"]}
+  }
+}`)}}
+	b, err := NewLayered([]Layer{{Sources: upgradeTestSources}, overlay})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("apply", func(t *testing.T) {
+		t.Parallel()
+
+		tests := map[string]struct {
+			apply   string
+			current string
+			want    string
+			code    codes.Code
+		}{
+			"recommended of next line": {apply: "major-recommended", current: "8.0.36-28.1", want: "8.4.3-3.1"},
+			"latest of next line":      {apply: "Major-Latest", current: "8.0.36-28.1", want: "8.4.3-3.1"},
+			"first edge with versions": {apply: "major-recommended", current: "5.7.44-31.65", want: "8.4.3-3.1"},
+			"no edge":                  {apply: "major-recommended", current: "8.4.2-2.1", want: "8.4.3-3.1"},
+			"no current version":       {apply: "major-recommended", want: "8.4.3-3.1"},
+			"invalid apply":            {apply: "major-8.4", current: "8.0.36-28.1", code: codes.InvalidArgument},
+		}
+		for name, tt := range tests {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				resp, err := b.Apply(ctx, &pbVersion.ApplyRequest{
+					Product:         "pxc-operator",
+					OperatorVersion: "1.16.0",
+					Apply:           tt.apply,
+					DatabaseVersion: tt.current,
+				})
+				if tt.code != codes.OK {
+					assert.Equal(t, tt.code, status.Code(err))
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, []string{tt.want}, slices.Collect(maps.Keys(resp.Versions[0].Matrix.Pxc)))
+			})
+		}
+	})
+
+	t.Run("plan", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := b.PlanUpgrade(ctx, &pbVersion.PlanUpgradeRequest{
+			Product:               "pxc-operator",
+			OperatorVersion:       "1.16.0",
+			DatabaseVersion:       "5.7.44-31.65",
+			TargetDatabaseVersion: "8.4",
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Hops, 1)
+		assert.Equal(t, "8.4.3-3.1", resp.Hops[0].DatabaseVersion)
+
+		_, err = b.PlanUpgrade(ctx, &pbVersion.PlanUpgradeRequest{
+			Product:               "pxc-operator",
+			OperatorVersion:       "1.16.0",
+			DatabaseVersion:       "5.7.44-31.65",
+			TargetDatabaseVersion: "8.0",
+		})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+}
@@ -0,0 +1,266 @@
+package server
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+	"github.com/diegoholiveira/jsonlogic/v3"
+)
+
+// ValidationIssue describes a single problem found in a source file.
+type ValidationIssue struct {
+	File string
+	// Key points to the offending entry inside the file, e.g. matrix.mongod.7.0.12-7.
+	// It is empty if the problem concerns the whole file.
+	Key    string
+	Reason string
+}
+
+func (i ValidationIssue) String() string {
+	if i.Key == "" {
+		return fmt.Sprintf("%s: %s", i.File, i.Reason)
+	}
+	return fmt.Sprintf("%s: %s: %s", i.File, i.Key, i.Reason)
+}
+
+// ValidationReport lists all problems found while loading sources.
+type ValidationReport struct {
+	Issues []ValidationIssue
+}
+
+func (r *ValidationReport) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "sources validation failed with %d issue(s):", len(r.Issues))
+	for _, i := range r.Issues {
+		b.WriteString("\n\t")
+		b.WriteString(i.String())
+	}
+	return b.String()
+}
+
+func (r *ValidationReport) add(file, key, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, ValidationIssue{File: file, Key: key, Reason: fmt.Sprintf(format, args...)})
+}
+
+// err returns the report sorted by file and key if it has any issues and nil otherwise.
+func (r *ValidationReport) err() error {
+	if len(r.Issues) == 0 {
+		return nil
+	}
+	slices.SortStableFunc(r.Issues, func(a, b ValidationIssue) int {
+		return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Key, b.Key))
+	})
+	return r
+}
+
+// validateVersionResponse checks a source file on its own. What depends on the other layers of the source
+// is checked once they are merged by validateMergedVersionResponse.
+func validateVersionResponse(file string, vs *pbVersion.VersionResponse, report *ValidationReport) {
+	if len(vs.Versions) == 0 {
+		report.add(file, "versions", "no operator versions")
+		return
+	}
+	for i, ov := range vs.Versions {
+		validateKubeVersions(file, fmt.Sprintf("versions.%d.supported_kube_versions", i), ov.SupportedKubeVersions, report)
+		if ov.Matrix == nil {
+			continue
+		}
+		rangeMatrix(ov.Matrix, func(component string, versions map[string]*pbVersion.Version) {
+			for k, ver := range versions {
+				key := fmt.Sprintf("matrix.%s.%s", component, k)
+				if _, err := semver.NewVersion(k); err != nil {
+					report.add(file, key, "invalid version: %v", err)
+				}
+				validateKubeVersions(file, key+".supported_kube_versions", ver.SupportedKubeVersions, report)
+				for _, name := range slices.Sorted(maps.Keys(ver.Distributions)) {
+					if !slices.Contains(distributions, name) {
+						report.add(file, key+".distributions."+name, "unknown distribution")
+					} else if ver.Distributions[name].GetImagePath() == "" {
+						report.add(file, key+".distributions."+name, "missing image path")
+					}
+				}
+				validateRollout(file, key+".rollout", ver.Rollout, report)
+			}
+		})
+	}
+}
+
+// validateMergedVersionResponse checks a source merged from all layers, as overlays may omit matrices,
+// statuses and parts of withdrawals. Issues are reported against the file defining the operator
+// or matrix version, or withdrawing the version for withdrawals.
+func validateMergedVersionResponse(o *sourceOrigin, vs *pbVersion.VersionResponse, report *ValidationReport) {
+	for i, ov := range vs.Versions {
+		if ov.Matrix == nil {
+			key := fmt.Sprintf("versions.%d", i)
+			report.add(o.defined[key], key+".matrix", "missing version matrix")
+			continue
+		}
+		rangeMatrix(ov.Matrix, func(component string, versions map[string]*pbVersion.Version) {
+			for k, ver := range versions {
+				key := fmt.Sprintf("matrix.%s.%s", component, k)
+				if ver.Status == pbVersion.Status_status_invalid {
+					report.add(o.defined[key], key, "unknown status")
+				}
+				validateWithdrawal(o.withdrawn[key], key+".withdrawn", ver.Withdrawn, versions, report)
+			}
+		})
+	}
+}
+
+// validateRollout checks that stages of the rollout have start times in ascending order and percentages up to 100.
+func validateRollout(file, key string, r *pbVersion.Rollout, report *ValidationReport) {
+	for i, s := range r.GetStages() {
+		stage := fmt.Sprintf("%s.stages.%d", key, i)
+		if s.Percent > 100 {
+			report.add(file, stage+".percent", "percent %d is above 100", s.Percent)
+		}
+		switch {
+		case s.Start == nil:
+			report.add(file, stage+".start", "missing start time")
+		case i > 0 && r.Stages[i-1].Start != nil && s.Start.AsTime().Before(r.Stages[i-1].Start.AsTime()):
+			report.add(file, stage+".start", "starts before the previous stage")
+		}
+	}
+}
+
+// validateWithdrawal checks that the withdrawal has a reason, a valid advisory URL
+// and a replacement which is another version of the component that is not withdrawn.
+func validateWithdrawal(file, key string, w *pbVersion.Withdrawal, versions map[string]*pbVersion.Version, report *ValidationReport) {
+	if w == nil {
+		return
+	}
+	if w.Reason == "" {
+		report.add(file, key+".reason", "missing reason")
+	}
+	if w.AdvisoryUrl != "" {
+		if u, err := url.Parse(w.AdvisoryUrl); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			report.add(file, key+".advisory_url", "invalid URL")
+		}
+	}
+	if w.Replacement != "" {
+		if r, ok := versions[w.Replacement]; !ok {
+			report.add(file, key+".replacement", "unknown version")
+		} else if r.Withdrawn != nil {
+			report.add(file, key+".replacement", "replacement is withdrawn")
+		}
+	}
+}
+
+func validateKubeVersions(file, key, constraint string, report *ValidationReport) {
+	if constraint == "" {
+		return
+	}
+	if _, err := semver.NewConstraint(constraint); err != nil {
+		report.add(file, key, "invalid constraint: %v", err)
+	}
+}
+
+func validateDeps(file string, deps Deps, report *ValidationReport) {
+	for component, rules := range deps {
+		for version, rule := range rules {
+			key := fmt.Sprintf("%s.%s", component, version)
+			if _, err := semver.NewVersion(version); err != nil {
+				report.add(file, key, "invalid version: %v", err)
+			}
+			if err := validateDepRule(rule); err != nil {
+				report.add(file, key, "invalid rule: %v", err)
+			}
+		}
+	}
+}
+
+// validateDepRule checks that rule is a valid jsonlogic expression which evaluates to a boolean.
+func validateDepRule(rule interface{}) (err error) {
+	b, err := json.Marshal(rule)
+	if err != nil {
+		return err
+	}
+	if !jsonlogic.IsValid(bytes.NewReader(b)) {
+		return errors.New("not a jsonlogic expression")
+	}
+	if err := validateSemverOperands(rule); err != nil {
+		return err
+	}
+
+	// jsonlogic panics on some malformed rules instead of returning an error
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to apply logic: %v", r)
+		}
+	}()
+	res, err := jsonlogic.ApplyInterface(rule, map[string]interface{}{"productVersion": "1.0.0"})
+	if err != nil {
+		return fmt.Errorf("failed to apply logic: %w", err)
+	}
+	if _, ok := res.(bool); !ok {
+		return fmt.Errorf("rule result %v is not a boolean", res)
+	}
+	return nil
+}
+
+// validateMetadata checks the metadata files of a layer, issues are reported against layer/path.
+func validateMetadata(layer string, fsys fs.FS, report *ValidationReport) {
+	m := &Metadata{}
+	walkProductFiles(layer, fsys, report, func(p string, content []byte) {
+		meta, err := m.parseFile(content, path.Ext(p))
+		if err != nil {
+			report.add(p, "", "%v", err)
+			return
+		}
+		if _, err := semver.NewVersion(meta.Version); err != nil {
+			report.add(p, "version", "invalid version: %v", err)
+		}
+		for component, constraint := range meta.Supported {
+			if _, err := semver.NewConstraint(constraint); err != nil {
+				report.add(p, "supported."+component, "invalid constraint: %v", err)
+			}
+		}
+		if meta.ImageInfo != nil && meta.ImageInfo.Status == pbVersion.Status_status_invalid {
+			report.add(p, "imageInfo.status", "unknown status")
+		}
+	})
+}
+
+// validateReleaseNotes checks the release notes files of a layer, issues are reported against layer/path.
+func validateReleaseNotes(layer string, fsys fs.FS, report *ValidationReport) {
+	walkProductFiles(layer, fsys, report, func(p string, content []byte) {
+		if path.Ext(p) != ".md" {
+			report.add(p, "", "extension %s not supported", path.Ext(p))
+		}
+	})
+}
+
+// walkProductFiles calls fn for every file in product directories of fsys of the layer with its path
+// prefixed by the layer name. Files in the root directory are ignored.
+func walkProductFiles(layer string, fsys fs.FS, report *ValidationReport, fn func(p string, content []byte)) {
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Dir(p) == "." {
+			return nil
+		}
+
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			report.add(path.Join(layer, p), "", "could not read file: %v", err)
+			return nil
+		}
+		fn(path.Join(layer, p), content)
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		report.add(path.Join(layer, "."), "", "could not read directory: %v", err)
+	}
+}
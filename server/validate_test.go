@@ -0,0 +1,87 @@
+package server
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackend_loadValidation(t *testing.T) {
+	t.Parallel()
+
+	sources := MemorySourceStore{
+		"operator.1.0.0.psmdb-operator.json": []byte(`{
+  "versions": [
+    {
+      "operator": "1.0.0",
+      "product": "psmdb-operator",
+      "matrix": {
+        "mongod": {
+          "7.0.15-9": {"image_path": "percona/percona-server-mongodb:7.0.15-9", "status": "recommended"},
+          "latest": {"image_path": "percona/percona-server-mongodb:latest", "status": "available"},
+          "8.0.4-1": {"image_path": "percona/percona-server-mongodb:8.0.4-1"}
+        }
+      }
+    }
+  ]
+}`),
+		"operator.1.0.0.psmdb-operator.dep.json": []byte(`{
+  "backup": {
+    "2.8.0": {">=": [{"var": "productVersion"}, "8.0"]},
+    "2.7.0": {"unknown": [{"var": "productVersion"}, "8.0"]}
+  }
+}`),
+		"operator.1.1.0.psmdb-operator.json": []byte(`{"versions": [{"matrix": {"mongod": {"8.0.4-1": {"status": "broken"}}}}]}`),
+		"operator.psmdb-operator.json":       []byte(`{}`),
+	}
+	metadata := fstest.MapFS{
+		"everest/1.0.0.yaml": {Data: []byte("version: one\nsupported:\n  pg: \">= foo\"\n")},
+		"everest/1.1.0.txt":  {Data: []byte("version: 1.1.0\n")},
+	}
+	releaseNotes := fstest.MapFS{
+		"pmm/3.0.0.md":  {Data: []byte("### PMM 3.0.0\n")},
+		"pmm/3.1.0.txt": {Data: []byte("PMM 3.1.0\n")},
+	}
+
+	_, err := New(sources, metadata, releaseNotes)
+	require.Error(t, err)
+
+	var report *ValidationReport
+	require.True(t, errors.As(err, &report))
+
+	type issue struct{ file, key string }
+	got := make([]issue, 0, len(report.Issues))
+	for _, i := range report.Issues {
+		assert.NotEmpty(t, i.Reason)
+		got = append(got, issue{i.File, i.Key})
+	}
+	assert.Equal(t, []issue{
+		{"everest/1.0.0.yaml", "supported.pg"},
+		{"everest/1.0.0.yaml", "version"},
+		{"everest/1.1.0.txt", ""},
+		{"operator.1.0.0.psmdb-operator.dep.json", "backup.2.7.0"},
+		{"operator.1.0.0.psmdb-operator.json", "matrix.mongod.8.0.4-1"},
+		{"operator.1.0.0.psmdb-operator.json", "matrix.mongod.latest"},
+		{"operator.1.1.0.psmdb-operator.json", ""},
+		{"operator.psmdb-operator.json", ""},
+		{"pmm/3.1.0.txt", ""},
+	}, got)
+}
+
+// validationIssueKeys returns the keys of the issues reported for the sources, which must be invalid.
+func validationIssueKeys(t *testing.T, sources SourceStore) []string {
+	t.Helper()
+
+	_, err := New(sources, fstest.MapFS{}, fstest.MapFS{})
+	var report *ValidationReport
+	require.True(t, errors.As(err, &report))
+
+	keys := make([]string, 0, len(report.Issues))
+	for _, i := range report.Issues {
+		keys = append(keys, i.Key)
+	}
+	return keys
+}
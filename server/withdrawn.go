@@ -0,0 +1,158 @@
+package server
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+)
+
+// withdrawals route Apply around withdrawn versions.
+type withdrawals struct {
+	// pins holds the versions the request pins keyed by matrix component.
+	pins     map[string]string
+	database string
+	policy   selectionPolicy
+	// running is the database version of the cluster and current the version Apply selects from:
+	// running or, if running is withdrawn, the version the cluster is routed to.
+	running, current string
+	// withdrawal is the withdrawal of running if it is withdrawn.
+	withdrawal *pbVersion.Withdrawal
+}
+
+// newWithdrawals returns withdrawals for the request. The request pins the database version it applies
+// as an exact version and the component versions running in the cluster.
+func newWithdrawals(req *pbVersion.ApplyRequest, pc ProductComponents) *withdrawals {
+	pins := pinnedVersions(req)
+	if pc.Database != "" {
+		pins[pc.Database] = req.Apply
+	}
+	return &withdrawals{
+		pins:     pins,
+		database: pc.Database,
+		policy:   selectionPolicies[pc.Policy],
+		running:  req.DatabaseVersion,
+		current:  req.DatabaseVersion,
+	}
+}
+
+// filter deletes withdrawn versions which are not pinned, so recommended and latest never select them,
+// and records the reasons in e. A cluster running a withdrawn database version is routed off it first.
+// It fails with FailedPrecondition if all database versions are withdrawn.
+func (w *withdrawals) filter(vs *pbVersion.VersionResponse, e *explanation) error {
+	for _, ov := range vs.Versions {
+		if ov.Matrix == nil {
+			continue
+		}
+		w.route(ov.Matrix)
+		var components []string
+		rangeMatrix(ov.Matrix, func(component string, versions map[string]*pbVersion.Version) {
+			if len(versions) > 0 {
+				components = append(components, component)
+			}
+		})
+		for _, component := range components {
+			t := e.component(component)
+			err := filterComponent(ov.Matrix, component, func(versions map[string]*pbVersion.Version) error {
+				maps.DeleteFunc(versions, func(k string, v *pbVersion.Version) bool {
+					if v.Withdrawn == nil || w.pins[component] == k || (component == w.database && k == w.current) {
+						return false
+					}
+					t.drop(k, "withdrawn: %s", v.Withdrawn.Reason)
+					return true
+				})
+				if component == w.database && len(versions) == 0 {
+					return status.Errorf(codes.FailedPrecondition, "all %s versions are withdrawn", component)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// route routes a cluster running a withdrawn database version to the replacement or, without one,
+// to the version of its line the policy selects. Without either, the cluster stays on its version.
+func (w *withdrawals) route(m *pbVersion.VersionMatrix) {
+	if w.database == "" || w.running == "" || w.pins[w.database] == w.running {
+		return
+	}
+	versions := componentVersions(m, w.database)
+	v, ok := versions[w.running]
+	if !ok || v.Withdrawn == nil {
+		return
+	}
+	w.withdrawal = v.Withdrawn
+	if r, ok := versions[v.Withdrawn.Replacement]; ok && r.Withdrawn == nil && r.Status != pbVersion.Status_disabled {
+		w.current = v.Withdrawn.Replacement
+	} else if lv, ok := w.policy.lineVersion(versions, w.running, false); ok {
+		w.current = lv
+	}
+}
+
+// check returns warnings for the withdrawn versions left in the matrix, the pinned ones and the running
+// database version without a version to route to, and for the routed database version. It fails with
+// FailedPrecondition and the withdrawal in the error details if a version left is blocked.
+func (w *withdrawals) check(m *pbVersion.VersionMatrix) ([]string, error) {
+	var warnings []string
+	if w.withdrawal != nil {
+		for k := range componentVersions(m, w.database) {
+			if k != w.running {
+				// the replacement is the selected version, don't advise it twice
+				withdrawal := &pbVersion.Withdrawal{Reason: w.withdrawal.Reason, AdvisoryUrl: w.withdrawal.AdvisoryUrl}
+				warnings = append(warnings, withdrawnMessage(w.database, w.running, withdrawal, k+" is selected instead"))
+			}
+		}
+	}
+
+	var components []string
+	rangeMatrix(m, func(component string, _ map[string]*pbVersion.Version) {
+		components = append(components, component)
+	})
+	slices.Sort(components)
+	for _, component := range components {
+		versions := componentVersions(m, component)
+		for _, k := range slices.Sorted(maps.Keys(versions)) {
+			v := versions[k]
+			if v.Withdrawn == nil {
+				continue
+			}
+			if v.Withdrawn.Blocked {
+				st, err := status.New(codes.FailedPrecondition, withdrawnMessage(component, k, v.Withdrawn, "must not be used")).
+					WithDetails(&pbVersion.WithdrawnVersion{Component: component, Version: k, Withdrawal: v.Withdrawn})
+				if err != nil {
+					return nil, status.Errorf(codes.Internal, "failed to add error details: %v", err)
+				}
+				return nil, st.Err()
+			}
+			warnings = append(warnings, withdrawnMessage(component, k, v.Withdrawn, "upgrade as soon as possible"))
+		}
+	}
+	return warnings, nil
+}
+
+// withdrawnMessage describes the withdrawal of version v of the component, e.g.
+// "mongod 8.0.4-1 is withdrawn: data loss on resharding, must not be used, use 8.0.5-2 instead, see https://…".
+func withdrawnMessage(component, v string, w *pbVersion.Withdrawal, advice string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s is withdrawn", component, v)
+	if w.Reason != "" {
+		fmt.Fprintf(&b, ": %s", w.Reason)
+	}
+	fmt.Fprintf(&b, ", %s", advice)
+	if w.Replacement != "" {
+		fmt.Fprintf(&b, ", use %s instead", w.Replacement)
+	}
+	if w.AdvisoryUrl != "" {
+		fmt.Fprintf(&b, ", see %s", w.AdvisoryUrl)
+	}
+	return b.String()
+}
@@ -0,0 +1,199 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pbVersion "github.com/Percona-Lab/percona-version-service/versionpb/api"
+)
+
+func TestBackend_withdrawn(t *testing.T) {
+	t.Parallel()
+
+	overlay := Layer{Sources: MemorySourceStore{
+		"operator.1.0.0.psmdb-operator.json": []byte(`{
+  "versions": [
+    {
+      "matrix": {
+        "mongod": {
+          "7.0.16-10": {
+            "image_path": "percona/percona-server-mongodb:7.0.16-10",
+            "status": "recommended",
+            "withdrawn": {
+              "reason": "crash on startup",
+              "advisory_url": "https://www.percona.com/advisories/psmdb-1",
+              "replacement": "7.0.15-9"
+            }
+          }
+        },
+        "backup": {
+          "2.7.1": {
+            "image_path": "percona/percona-backup-mongodb:2.7.1",
+            "status": "recommended",
+            "withdrawn": {"reason": "corrupts backups", "replacement": "2.7.0", "blocked": true}
+          }
+        }
+      }
+    }
+  ]
+}`),
+		"operator.1.0.0.psmdb-operator.dep.json": []byte(`{
+  "backup": {
+    "2.7.1": {"<": [{"var": "productVersion"}, "8.0"]}
+  }
+}`),
+	}}
+	b, err := NewLayered([]Layer{{Sources: testSources}, overlay})
+	require.NoError(t, err)
+
+	routedWarnings := []string{
+		"mongod 7.0.16-10 is withdrawn: crash on startup, 7.0.15-9 is selected instead, " +
+			"see https://www.percona.com/advisories/psmdb-1",
+	}
+	tests := map[string]struct {
+		apply        string
+		current      string
+		wantMongod   string
+		wantBackup   string
+		wantWarnings []string
+	}{
+		"recommended": {apply: "recommended", current: "7.0.14-8", wantMongod: "7.0.15-9", wantBackup: "2.7.0"},
+		"latest":      {apply: "latest", current: "7.0.14-8", wantMongod: "7.0.15-9", wantBackup: "2.7.0"},
+		"pinned": {
+			apply:      "7.0.16-10",
+			current:    "7.0.14-8",
+			wantMongod: "7.0.16-10",
+			wantBackup: "2.7.0",
+			wantWarnings: []string{
+				"mongod 7.0.16-10 is withdrawn: crash on startup, upgrade as soon as possible, use 7.0.15-9 instead, " +
+					"see https://www.percona.com/advisories/psmdb-1",
+			},
+		},
+		"current version withdrawn, nothing newer": {
+			apply:        "recommended",
+			current:      "7.0.16-10",
+			wantMongod:   "7.0.15-9",
+			wantBackup:   "2.7.0",
+			wantWarnings: routedWarnings,
+		},
+		"current version withdrawn, latest": {
+			apply:        "latest",
+			current:      "7.0.16-10",
+			wantMongod:   "7.0.15-9",
+			wantBackup:   "2.7.0",
+			wantWarnings: routedWarnings,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resp, err := b.Apply(context.Background(), &pbVersion.ApplyRequest{
+				Product:         "psmdb-operator",
+				OperatorVersion: "1.0.0",
+				Apply:           tt.apply,
+				DatabaseVersion: tt.current,
+			})
+			require.NoError(t, err)
+
+			matrix := resp.Versions[0].Matrix
+			require.Len(t, matrix.Mongod, 1)
+			assert.Contains(t, matrix.Mongod, tt.wantMongod)
+			assert.Contains(t, matrix.Backup, tt.wantBackup)
+			assert.Equal(t, tt.wantWarnings, resp.Warnings)
+		})
+	}
+
+	_, err = b.Apply(context.Background(), &pbVersion.ApplyRequest{
+		Product:         "psmdb-operator",
+		OperatorVersion: "1.0.0",
+		Apply:           "recommended",
+		DatabaseVersion: "7.0.14-8",
+		BackupVersion:   "2.7.1",
+	})
+	st := status.Convert(err)
+	assert.Equal(t, codes.FailedPrecondition, st.Code())
+	assert.Equal(t, "backup 2.7.1 is withdrawn: corrupts backups, must not be used, use 2.7.0 instead", st.Message())
+	require.Len(t, st.Details(), 1)
+	detail, ok := st.Details()[0].(*pbVersion.WithdrawnVersion)
+	require.True(t, ok)
+	assert.Equal(t, "backup", detail.Component)
+	assert.Equal(t, "2.7.1", detail.Version)
+	assert.Equal(t, "2.7.0", detail.Withdrawal.Replacement)
+}
+
+func TestBackend_withdrawnOnlyCandidate(t *testing.T) {
+	t.Parallel()
+
+	overlay := Layer{Sources: MemorySourceStore{
+		"operator.1.0.0.psmdb-operator.json": []byte(`{
+  "versions": [
+    {
+      "matrix": {
+        "mongod": {
+          "7.0.14-8": {"withdrawn": {"reason": "crash on startup"}},
+          "7.0.15-9": {"withdrawn": {"reason": "crash on startup"}},
+          "8.0.4-1": {"withdrawn": {"reason": "data loss"}}
+        }
+      }
+    }
+  ]
+}`),
+	}}
+	b, err := NewLayered([]Layer{{Sources: testSources}, overlay})
+	require.NoError(t, err)
+
+	for _, apply := range []string{"recommended", "latest"} {
+		resp, err := b.Apply(context.Background(), &pbVersion.ApplyRequest{
+			Product:         "psmdb-operator",
+			OperatorVersion: "1.0.0",
+			Apply:           apply,
+			DatabaseVersion: "7.0.15-9",
+		})
+		require.NoError(t, err)
+		assert.Contains(t, resp.Versions[0].Matrix.Mongod, "7.0.15-9")
+		assert.Equal(t, []string{"mongod 7.0.15-9 is withdrawn: crash on startup, upgrade as soon as possible"}, resp.Warnings)
+
+		_, err = b.Apply(context.Background(), &pbVersion.ApplyRequest{
+			Product:         "psmdb-operator",
+			OperatorVersion: "1.0.0",
+			Apply:           apply,
+		})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	}
+}
+
+func TestValidateWithdrawal(t *testing.T) {
+	t.Parallel()
+
+	sources := MemorySourceStore{
+		"operator.1.0.0.psmdb-operator.json": []byte(`{
+  "versions": [
+    {
+      "matrix": {
+        "mongod": {
+          "7.0.15-9": {"status": "recommended", "withdrawn": {"reason": "crash"}},
+          "7.0.16-10": {
+            "status": "recommended",
+            "withdrawn": {"advisory_url": "percona.com/advisories", "replacement": "7.0.15-9"}
+          },
+          "8.0.4-1": {"status": "available", "withdrawn": {"reason": "crash", "replacement": "8.0.5-2"}}
+        }
+      }
+    }
+  ]
+}`),
+	}
+
+	assert.Equal(t, []string{
+		"matrix.mongod.7.0.16-10.withdrawn.advisory_url",
+		"matrix.mongod.7.0.16-10.withdrawn.reason",
+		"matrix.mongod.7.0.16-10.withdrawn.replacement",
+		"matrix.mongod.8.0.4-1.withdrawn.replacement",
+	}, validationIssueKeys(t, sources))
+}
@@ -81,39 +81,46 @@ func (Status) EnumDescriptor() ([]byte, []int) {
 }
 
 type ApplyRequest struct {
-	state                   protoimpl.MessageState `protogen:"open.v1"`
-	Product                 string                 `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
-	OperatorVersion         string                 `protobuf:"bytes,2,opt,name=operator_version,json=operatorVersion,proto3" json:"operator_version,omitempty"`
-	Apply                   string                 `protobuf:"bytes,3,opt,name=apply,proto3" json:"apply,omitempty"`
-	DatabaseVersion         string                 `protobuf:"bytes,4,opt,name=database_version,json=databaseVersion,proto3" json:"database_version,omitempty"`
-	KubeVersion             string                 `protobuf:"bytes,5,opt,name=kube_version,json=kubeVersion,proto3" json:"kube_version,omitempty"`
-	Platform                string                 `protobuf:"bytes,6,opt,name=platform,proto3" json:"platform,omitempty"`
-	PmmVersion              string                 `protobuf:"bytes,7,opt,name=pmm_version,json=pmmVersion,proto3" json:"pmm_version,omitempty"`
-	BackupVersion           string                 `protobuf:"bytes,8,opt,name=backup_version,json=backupVersion,proto3" json:"backup_version,omitempty"`
-	ProxysqlVersion         string                 `protobuf:"bytes,9,opt,name=proxysql_version,json=proxysqlVersion,proto3" json:"proxysql_version,omitempty"`
-	HaproxyVersion          string                 `protobuf:"bytes,10,opt,name=haproxy_version,json=haproxyVersion,proto3" json:"haproxy_version,omitempty"`
-	NamespaceUid            string                 `protobuf:"bytes,11,opt,name=namespace_uid,json=namespaceUid,proto3" json:"namespace_uid,omitempty"`
-	CustomResourceUid       string                 `protobuf:"bytes,12,opt,name=custom_resource_uid,json=customResourceUid,proto3" json:"custom_resource_uid,omitempty"`
-	LogCollectorVersion     string                 `protobuf:"bytes,13,opt,name=log_collector_version,json=logCollectorVersion,proto3" json:"log_collector_version,omitempty"`
-	ShardingEnabled         bool                   `protobuf:"varint,14,opt,name=sharding_enabled,json=shardingEnabled,proto3" json:"sharding_enabled,omitempty"`
-	HashicorpVaultEnabled   bool                   `protobuf:"varint,15,opt,name=hashicorp_vault_enabled,json=hashicorpVaultEnabled,proto3" json:"hashicorp_vault_enabled,omitempty"`
-	ClusterWideEnabled      bool                   `protobuf:"varint,16,opt,name=cluster_wide_enabled,json=clusterWideEnabled,proto3" json:"cluster_wide_enabled,omitempty"`
-	PmmEnabled              bool                   `protobuf:"varint,17,opt,name=pmm_enabled,json=pmmEnabled,proto3" json:"pmm_enabled,omitempty"`
-	HelmDeployOperator      bool                   `protobuf:"varint,18,opt,name=helm_deploy_operator,json=helmDeployOperator,proto3" json:"helm_deploy_operator,omitempty"`
-	HelmDeployCr            bool                   `protobuf:"varint,19,opt,name=helm_deploy_cr,json=helmDeployCr,proto3" json:"helm_deploy_cr,omitempty"`
-	SidecarsUsed            bool                   `protobuf:"varint,20,opt,name=sidecars_used,json=sidecarsUsed,proto3" json:"sidecars_used,omitempty"`
-	BackupsEnabled          bool                   `protobuf:"varint,21,opt,name=backups_enabled,json=backupsEnabled,proto3" json:"backups_enabled,omitempty"`
-	ClusterSize             int32                  `protobuf:"varint,22,opt,name=cluster_size,json=clusterSize,proto3" json:"cluster_size,omitempty"`
-	PitrEnabled             bool                   `protobuf:"varint,23,opt,name=pitr_enabled,json=pitrEnabled,proto3" json:"pitr_enabled,omitempty"`
-	PhysicalBackupScheduled bool                   `protobuf:"varint,24,opt,name=physical_backup_scheduled,json=physicalBackupScheduled,proto3" json:"physical_backup_scheduled,omitempty"`
-	Extensions              string                 `protobuf:"bytes,25,opt,name=extensions,proto3" json:"extensions,omitempty"`
-	UserManagementEnabled   bool                   `protobuf:"varint,26,opt,name=user_management_enabled,json=userManagementEnabled,proto3" json:"user_management_enabled,omitempty"`
-	RoleManagementEnabled   bool                   `protobuf:"varint,27,opt,name=role_management_enabled,json=roleManagementEnabled,proto3" json:"role_management_enabled,omitempty"`
-	McsEnabled              bool                   `protobuf:"varint,28,opt,name=mcs_enabled,json=mcsEnabled,proto3" json:"mcs_enabled,omitempty"`
-	VolumeExpansionEnabled  bool                   `protobuf:"varint,29,opt,name=volume_expansion_enabled,json=volumeExpansionEnabled,proto3" json:"volume_expansion_enabled,omitempty"`
-	Distribution            string                 `protobuf:"bytes,30,opt,name=distribution,proto3" json:"distribution,omitempty"`
-	unknownFields           protoimpl.UnknownFields
-	sizeCache               protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Product         string                 `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	OperatorVersion string                 `protobuf:"bytes,2,opt,name=operator_version,json=operatorVersion,proto3" json:"operator_version,omitempty"`
+	Apply           string                 `protobuf:"bytes,3,opt,name=apply,proto3" json:"apply,omitempty"`
+	DatabaseVersion string                 `protobuf:"bytes,4,opt,name=database_version,json=databaseVersion,proto3" json:"database_version,omitempty"`
+	KubeVersion     string                 `protobuf:"bytes,5,opt,name=kube_version,json=kubeVersion,proto3" json:"kube_version,omitempty"`
+	Platform        string                 `protobuf:"bytes,6,opt,name=platform,proto3" json:"platform,omitempty"`
+	// pmm_version, backup_version, proxysql_version, haproxy_version and log_collector_version pin
+	// the versions of the components running in the cluster. Apply keeps them while they are compatible
+	// with the selected database version and warns when it selects another version instead.
+	PmmVersion              string `protobuf:"bytes,7,opt,name=pmm_version,json=pmmVersion,proto3" json:"pmm_version,omitempty"`
+	BackupVersion           string `protobuf:"bytes,8,opt,name=backup_version,json=backupVersion,proto3" json:"backup_version,omitempty"`
+	ProxysqlVersion         string `protobuf:"bytes,9,opt,name=proxysql_version,json=proxysqlVersion,proto3" json:"proxysql_version,omitempty"`
+	HaproxyVersion          string `protobuf:"bytes,10,opt,name=haproxy_version,json=haproxyVersion,proto3" json:"haproxy_version,omitempty"`
+	NamespaceUid            string `protobuf:"bytes,11,opt,name=namespace_uid,json=namespaceUid,proto3" json:"namespace_uid,omitempty"`
+	CustomResourceUid       string `protobuf:"bytes,12,opt,name=custom_resource_uid,json=customResourceUid,proto3" json:"custom_resource_uid,omitempty"`
+	LogCollectorVersion     string `protobuf:"bytes,13,opt,name=log_collector_version,json=logCollectorVersion,proto3" json:"log_collector_version,omitempty"`
+	ShardingEnabled         bool   `protobuf:"varint,14,opt,name=sharding_enabled,json=shardingEnabled,proto3" json:"sharding_enabled,omitempty"`
+	HashicorpVaultEnabled   bool   `protobuf:"varint,15,opt,name=hashicorp_vault_enabled,json=hashicorpVaultEnabled,proto3" json:"hashicorp_vault_enabled,omitempty"`
+	ClusterWideEnabled      bool   `protobuf:"varint,16,opt,name=cluster_wide_enabled,json=clusterWideEnabled,proto3" json:"cluster_wide_enabled,omitempty"`
+	PmmEnabled              bool   `protobuf:"varint,17,opt,name=pmm_enabled,json=pmmEnabled,proto3" json:"pmm_enabled,omitempty"`
+	HelmDeployOperator      bool   `protobuf:"varint,18,opt,name=helm_deploy_operator,json=helmDeployOperator,proto3" json:"helm_deploy_operator,omitempty"`
+	HelmDeployCr            bool   `protobuf:"varint,19,opt,name=helm_deploy_cr,json=helmDeployCr,proto3" json:"helm_deploy_cr,omitempty"`
+	SidecarsUsed            bool   `protobuf:"varint,20,opt,name=sidecars_used,json=sidecarsUsed,proto3" json:"sidecars_used,omitempty"`
+	BackupsEnabled          bool   `protobuf:"varint,21,opt,name=backups_enabled,json=backupsEnabled,proto3" json:"backups_enabled,omitempty"`
+	ClusterSize             int32  `protobuf:"varint,22,opt,name=cluster_size,json=clusterSize,proto3" json:"cluster_size,omitempty"`
+	PitrEnabled             bool   `protobuf:"varint,23,opt,name=pitr_enabled,json=pitrEnabled,proto3" json:"pitr_enabled,omitempty"`
+	PhysicalBackupScheduled bool   `protobuf:"varint,24,opt,name=physical_backup_scheduled,json=physicalBackupScheduled,proto3" json:"physical_backup_scheduled,omitempty"`
+	Extensions              string `protobuf:"bytes,25,opt,name=extensions,proto3" json:"extensions,omitempty"`
+	UserManagementEnabled   bool   `protobuf:"varint,26,opt,name=user_management_enabled,json=userManagementEnabled,proto3" json:"user_management_enabled,omitempty"`
+	RoleManagementEnabled   bool   `protobuf:"varint,27,opt,name=role_management_enabled,json=roleManagementEnabled,proto3" json:"role_management_enabled,omitempty"`
+	McsEnabled              bool   `protobuf:"varint,28,opt,name=mcs_enabled,json=mcsEnabled,proto3" json:"mcs_enabled,omitempty"`
+	VolumeExpansionEnabled  bool   `protobuf:"varint,29,opt,name=volume_expansion_enabled,json=volumeExpansionEnabled,proto3" json:"volume_expansion_enabled,omitempty"`
+	Distribution            string `protobuf:"bytes,30,opt,name=distribution,proto3" json:"distribution,omitempty"`
+	// registry selects a named set of registry rewrite rules applied to image paths.
+	Registry      string `protobuf:"bytes,31,opt,name=registry,proto3" json:"registry,omitempty"`
+	Arch          string `protobuf:"bytes,32,opt,name=arch,proto3" json:"arch,omitempty"`
+	Explain       bool   `protobuf:"varint,33,opt,name=explain,proto3" json:"explain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ApplyRequest) Reset() {
@@ -356,6 +363,27 @@ func (x *ApplyRequest) GetDistribution() string {
 	return ""
 }
 
+func (x *ApplyRequest) GetRegistry() string {
+	if x != nil {
+		return x.Registry
+	}
+	return ""
+}
+
+func (x *ApplyRequest) GetArch() string {
+	if x != nil {
+		return x.Arch
+	}
+	return ""
+}
+
+func (x *ApplyRequest) GetExplain() bool {
+	if x != nil {
+		return x.Explain
+	}
+	return false
+}
+
 type OperatorRequest struct {
 	state                   protoimpl.MessageState `protogen:"open.v1"`
 	Product                 string                 `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
@@ -387,8 +415,10 @@ type OperatorRequest struct {
 	McsEnabled              bool                   `protobuf:"varint,28,opt,name=mcs_enabled,json=mcsEnabled,proto3" json:"mcs_enabled,omitempty"`
 	VolumeExpansionEnabled  bool                   `protobuf:"varint,29,opt,name=volume_expansion_enabled,json=volumeExpansionEnabled,proto3" json:"volume_expansion_enabled,omitempty"`
 	Distribution            string                 `protobuf:"bytes,30,opt,name=distribution,proto3" json:"distribution,omitempty"`
-	unknownFields           protoimpl.UnknownFields
-	sizeCache               protoimpl.SizeCache
+	// registry selects a named set of registry rewrite rules applied to image paths.
+	Registry      string `protobuf:"bytes,31,opt,name=registry,proto3" json:"registry,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *OperatorRequest) Reset() {
@@ -624,38 +654,53 @@ func (x *OperatorRequest) GetDistribution() string {
 	return ""
 }
 
+func (x *OperatorRequest) GetRegistry() string {
+	if x != nil {
+		return x.Registry
+	}
+	return ""
+}
+
 type ProductRequest struct {
-	state                   protoimpl.MessageState `protogen:"open.v1"`
-	Product                 string                 `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
-	DatabaseVersion         string                 `protobuf:"bytes,4,opt,name=database_version,json=databaseVersion,proto3" json:"database_version,omitempty"`
-	KubeVersion             string                 `protobuf:"bytes,5,opt,name=kube_version,json=kubeVersion,proto3" json:"kube_version,omitempty"`
-	Platform                string                 `protobuf:"bytes,6,opt,name=platform,proto3" json:"platform,omitempty"`
-	PmmVersion              string                 `protobuf:"bytes,7,opt,name=pmm_version,json=pmmVersion,proto3" json:"pmm_version,omitempty"`
-	BackupVersion           string                 `protobuf:"bytes,8,opt,name=backup_version,json=backupVersion,proto3" json:"backup_version,omitempty"`
-	ProxysqlVersion         string                 `protobuf:"bytes,9,opt,name=proxysql_version,json=proxysqlVersion,proto3" json:"proxysql_version,omitempty"`
-	HaproxyVersion          string                 `protobuf:"bytes,10,opt,name=haproxy_version,json=haproxyVersion,proto3" json:"haproxy_version,omitempty"`
-	NamespaceUid            string                 `protobuf:"bytes,11,opt,name=namespace_uid,json=namespaceUid,proto3" json:"namespace_uid,omitempty"`
-	CustomResourceUid       string                 `protobuf:"bytes,12,opt,name=custom_resource_uid,json=customResourceUid,proto3" json:"custom_resource_uid,omitempty"`
-	LogCollectorVersion     string                 `protobuf:"bytes,13,opt,name=log_collector_version,json=logCollectorVersion,proto3" json:"log_collector_version,omitempty"`
-	ShardingEnabled         bool                   `protobuf:"varint,14,opt,name=sharding_enabled,json=shardingEnabled,proto3" json:"sharding_enabled,omitempty"`
-	HashicorpVaultEnabled   bool                   `protobuf:"varint,15,opt,name=hashicorp_vault_enabled,json=hashicorpVaultEnabled,proto3" json:"hashicorp_vault_enabled,omitempty"`
-	ClusterWideEnabled      bool                   `protobuf:"varint,16,opt,name=cluster_wide_enabled,json=clusterWideEnabled,proto3" json:"cluster_wide_enabled,omitempty"`
-	PmmEnabled              bool                   `protobuf:"varint,17,opt,name=pmm_enabled,json=pmmEnabled,proto3" json:"pmm_enabled,omitempty"`
-	HelmDeployOperator      bool                   `protobuf:"varint,18,opt,name=helm_deploy_operator,json=helmDeployOperator,proto3" json:"helm_deploy_operator,omitempty"`
-	HelmDeployCr            bool                   `protobuf:"varint,19,opt,name=helm_deploy_cr,json=helmDeployCr,proto3" json:"helm_deploy_cr,omitempty"`
-	SidecarsUsed            bool                   `protobuf:"varint,20,opt,name=sidecars_used,json=sidecarsUsed,proto3" json:"sidecars_used,omitempty"`
-	BackupsEnabled          bool                   `protobuf:"varint,21,opt,name=backups_enabled,json=backupsEnabled,proto3" json:"backups_enabled,omitempty"`
-	ClusterSize             int32                  `protobuf:"varint,22,opt,name=cluster_size,json=clusterSize,proto3" json:"cluster_size,omitempty"`
-	PitrEnabled             bool                   `protobuf:"varint,23,opt,name=pitr_enabled,json=pitrEnabled,proto3" json:"pitr_enabled,omitempty"`
-	PhysicalBackupScheduled bool                   `protobuf:"varint,24,opt,name=physical_backup_scheduled,json=physicalBackupScheduled,proto3" json:"physical_backup_scheduled,omitempty"`
-	Extensions              string                 `protobuf:"bytes,25,opt,name=extensions,proto3" json:"extensions,omitempty"`
-	UserManagementEnabled   bool                   `protobuf:"varint,26,opt,name=user_management_enabled,json=userManagementEnabled,proto3" json:"user_management_enabled,omitempty"`
-	RoleManagementEnabled   bool                   `protobuf:"varint,27,opt,name=role_management_enabled,json=roleManagementEnabled,proto3" json:"role_management_enabled,omitempty"`
-	McsEnabled              bool                   `protobuf:"varint,28,opt,name=mcs_enabled,json=mcsEnabled,proto3" json:"mcs_enabled,omitempty"`
-	VolumeExpansionEnabled  bool                   `protobuf:"varint,29,opt,name=volume_expansion_enabled,json=volumeExpansionEnabled,proto3" json:"volume_expansion_enabled,omitempty"`
-	Distribution            string                 `protobuf:"bytes,30,opt,name=distribution,proto3" json:"distribution,omitempty"`
-	unknownFields           protoimpl.UnknownFields
-	sizeCache               protoimpl.SizeCache
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Product string                 `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	// operator_version filters operator versions by a semver constraint, e.g. ">=2.5.0".
+	OperatorVersion         string `protobuf:"bytes,2,opt,name=operator_version,json=operatorVersion,proto3" json:"operator_version,omitempty"`
+	DatabaseVersion         string `protobuf:"bytes,4,opt,name=database_version,json=databaseVersion,proto3" json:"database_version,omitempty"`
+	KubeVersion             string `protobuf:"bytes,5,opt,name=kube_version,json=kubeVersion,proto3" json:"kube_version,omitempty"`
+	Platform                string `protobuf:"bytes,6,opt,name=platform,proto3" json:"platform,omitempty"`
+	PmmVersion              string `protobuf:"bytes,7,opt,name=pmm_version,json=pmmVersion,proto3" json:"pmm_version,omitempty"`
+	BackupVersion           string `protobuf:"bytes,8,opt,name=backup_version,json=backupVersion,proto3" json:"backup_version,omitempty"`
+	ProxysqlVersion         string `protobuf:"bytes,9,opt,name=proxysql_version,json=proxysqlVersion,proto3" json:"proxysql_version,omitempty"`
+	HaproxyVersion          string `protobuf:"bytes,10,opt,name=haproxy_version,json=haproxyVersion,proto3" json:"haproxy_version,omitempty"`
+	NamespaceUid            string `protobuf:"bytes,11,opt,name=namespace_uid,json=namespaceUid,proto3" json:"namespace_uid,omitempty"`
+	CustomResourceUid       string `protobuf:"bytes,12,opt,name=custom_resource_uid,json=customResourceUid,proto3" json:"custom_resource_uid,omitempty"`
+	LogCollectorVersion     string `protobuf:"bytes,13,opt,name=log_collector_version,json=logCollectorVersion,proto3" json:"log_collector_version,omitempty"`
+	ShardingEnabled         bool   `protobuf:"varint,14,opt,name=sharding_enabled,json=shardingEnabled,proto3" json:"sharding_enabled,omitempty"`
+	HashicorpVaultEnabled   bool   `protobuf:"varint,15,opt,name=hashicorp_vault_enabled,json=hashicorpVaultEnabled,proto3" json:"hashicorp_vault_enabled,omitempty"`
+	ClusterWideEnabled      bool   `protobuf:"varint,16,opt,name=cluster_wide_enabled,json=clusterWideEnabled,proto3" json:"cluster_wide_enabled,omitempty"`
+	PmmEnabled              bool   `protobuf:"varint,17,opt,name=pmm_enabled,json=pmmEnabled,proto3" json:"pmm_enabled,omitempty"`
+	HelmDeployOperator      bool   `protobuf:"varint,18,opt,name=helm_deploy_operator,json=helmDeployOperator,proto3" json:"helm_deploy_operator,omitempty"`
+	HelmDeployCr            bool   `protobuf:"varint,19,opt,name=helm_deploy_cr,json=helmDeployCr,proto3" json:"helm_deploy_cr,omitempty"`
+	SidecarsUsed            bool   `protobuf:"varint,20,opt,name=sidecars_used,json=sidecarsUsed,proto3" json:"sidecars_used,omitempty"`
+	BackupsEnabled          bool   `protobuf:"varint,21,opt,name=backups_enabled,json=backupsEnabled,proto3" json:"backups_enabled,omitempty"`
+	ClusterSize             int32  `protobuf:"varint,22,opt,name=cluster_size,json=clusterSize,proto3" json:"cluster_size,omitempty"`
+	PitrEnabled             bool   `protobuf:"varint,23,opt,name=pitr_enabled,json=pitrEnabled,proto3" json:"pitr_enabled,omitempty"`
+	PhysicalBackupScheduled bool   `protobuf:"varint,24,opt,name=physical_backup_scheduled,json=physicalBackupScheduled,proto3" json:"physical_backup_scheduled,omitempty"`
+	Extensions              string `protobuf:"bytes,25,opt,name=extensions,proto3" json:"extensions,omitempty"`
+	UserManagementEnabled   bool   `protobuf:"varint,26,opt,name=user_management_enabled,json=userManagementEnabled,proto3" json:"user_management_enabled,omitempty"`
+	RoleManagementEnabled   bool   `protobuf:"varint,27,opt,name=role_management_enabled,json=roleManagementEnabled,proto3" json:"role_management_enabled,omitempty"`
+	McsEnabled              bool   `protobuf:"varint,28,opt,name=mcs_enabled,json=mcsEnabled,proto3" json:"mcs_enabled,omitempty"`
+	VolumeExpansionEnabled  bool   `protobuf:"varint,29,opt,name=volume_expansion_enabled,json=volumeExpansionEnabled,proto3" json:"volume_expansion_enabled,omitempty"`
+	Distribution            string `protobuf:"bytes,30,opt,name=distribution,proto3" json:"distribution,omitempty"`
+	// registry selects a named set of registry rewrite rules applied to image paths.
+	Registry string `protobuf:"bytes,31,opt,name=registry,proto3" json:"registry,omitempty"`
+	// page_size is the maximum number of operator versions to return. All of them are returned if it is not set.
+	PageSize int32 `protobuf:"varint,32,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token is the next_page_token of the previous response.
+	PageToken     string `protobuf:"bytes,33,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ProductRequest) Reset() {
@@ -695,6 +740,13 @@ func (x *ProductRequest) GetProduct() string {
 	return ""
 }
 
+func (x *ProductRequest) GetOperatorVersion() string {
+	if x != nil {
+		return x.OperatorVersion
+	}
+	return ""
+}
+
 func (x *ProductRequest) GetDatabaseVersion() string {
 	if x != nil {
 		return x.DatabaseVersion
@@ -884,6 +936,27 @@ func (x *ProductRequest) GetDistribution() string {
 	return ""
 }
 
+func (x *ProductRequest) GetRegistry() string {
+	if x != nil {
+		return x.Registry
+	}
+	return ""
+}
+
+func (x *ProductRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ProductRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
 type MetadataRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Product       string                 `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
@@ -928,32 +1001,35 @@ func (x *MetadataRequest) GetProduct() string {
 	return ""
 }
 
-// Version represents product version information.
-type Version struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	ImagePath      string                 `protobuf:"bytes,1,opt,name=image_path,json=imagePath,proto3" json:"image_path,omitempty"`
-	ImageHash      string                 `protobuf:"bytes,2,opt,name=image_hash,json=imageHash,proto3" json:"image_hash,omitempty"`
-	ImageHashArm64 string                 `protobuf:"bytes,3,opt,name=image_hash_arm64,json=imageHashArm64,proto3" json:"image_hash_arm64,omitempty"`
-	Status         Status                 `protobuf:"varint,4,opt,name=status,proto3,enum=version.Status" json:"status,omitempty"`
-	Critical       bool                   `protobuf:"varint,5,opt,name=critical,proto3" json:"critical,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+type PlanUpgradeRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Product string                 `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	// operator_version is the running operator version.
+	OperatorVersion string `protobuf:"bytes,2,opt,name=operator_version,json=operatorVersion,proto3" json:"operator_version,omitempty"`
+	// database_version is the running database version.
+	DatabaseVersion       string `protobuf:"bytes,3,opt,name=database_version,json=databaseVersion,proto3" json:"database_version,omitempty"`
+	TargetOperatorVersion string `protobuf:"bytes,4,opt,name=target_operator_version,json=targetOperatorVersion,proto3" json:"target_operator_version,omitempty"`
+	TargetDatabaseVersion string `protobuf:"bytes,5,opt,name=target_database_version,json=targetDatabaseVersion,proto3" json:"target_database_version,omitempty"`
+	// registry selects a named set of registry rewrite rules applied to image paths.
+	Registry      string `protobuf:"bytes,6,opt,name=registry,proto3" json:"registry,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Version) Reset() {
-	*x = Version{}
+func (x *PlanUpgradeRequest) Reset() {
+	*x = PlanUpgradeRequest{}
 	mi := &file_api_version_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Version) String() string {
+func (x *PlanUpgradeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Version) ProtoMessage() {}
+func (*PlanUpgradeRequest) ProtoMessage() {}
 
-func (x *Version) ProtoReflect() protoreflect.Message {
+func (x *PlanUpgradeRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_api_version_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -965,46 +1041,607 @@ func (x *Version) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Version.ProtoReflect.Descriptor instead.
-func (*Version) Descriptor() ([]byte, []int) {
+// Deprecated: Use PlanUpgradeRequest.ProtoReflect.Descriptor instead.
+func (*PlanUpgradeRequest) Descriptor() ([]byte, []int) {
 	return file_api_version_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *Version) GetImagePath() string {
+func (x *PlanUpgradeRequest) GetProduct() string {
+	if x != nil {
+		return x.Product
+	}
+	return ""
+}
+
+func (x *PlanUpgradeRequest) GetOperatorVersion() string {
+	if x != nil {
+		return x.OperatorVersion
+	}
+	return ""
+}
+
+func (x *PlanUpgradeRequest) GetDatabaseVersion() string {
+	if x != nil {
+		return x.DatabaseVersion
+	}
+	return ""
+}
+
+func (x *PlanUpgradeRequest) GetTargetOperatorVersion() string {
+	if x != nil {
+		return x.TargetOperatorVersion
+	}
+	return ""
+}
+
+func (x *PlanUpgradeRequest) GetTargetDatabaseVersion() string {
+	if x != nil {
+		return x.TargetDatabaseVersion
+	}
+	return ""
+}
+
+func (x *PlanUpgradeRequest) GetRegistry() string {
+	if x != nil {
+		return x.Registry
+	}
+	return ""
+}
+
+// UpgradeHop is a single step of an upgrade plan.
+type UpgradeHop struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	OperatorVersion string                 `protobuf:"bytes,1,opt,name=operator_version,json=operatorVersion,proto3" json:"operator_version,omitempty"`
+	DatabaseVersion string                 `protobuf:"bytes,2,opt,name=database_version,json=databaseVersion,proto3" json:"database_version,omitempty"`
+	// matrix holds the versions of all components to run after the step.
+	Matrix *VersionMatrix `protobuf:"bytes,3,opt,name=matrix,proto3" json:"matrix,omitempty"`
+	// reason explains why the step is needed.
+	Reason        string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpgradeHop) Reset() {
+	*x = UpgradeHop{}
+	mi := &file_api_version_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpgradeHop) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpgradeHop) ProtoMessage() {}
+
+func (x *UpgradeHop) ProtoReflect() protoreflect.Message {
+	mi := &file_api_version_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpgradeHop.ProtoReflect.Descriptor instead.
+func (*UpgradeHop) Descriptor() ([]byte, []int) {
+	return file_api_version_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UpgradeHop) GetOperatorVersion() string {
+	if x != nil {
+		return x.OperatorVersion
+	}
+	return ""
+}
+
+func (x *UpgradeHop) GetDatabaseVersion() string {
+	if x != nil {
+		return x.DatabaseVersion
+	}
+	return ""
+}
+
+func (x *UpgradeHop) GetMatrix() *VersionMatrix {
+	if x != nil {
+		return x.Matrix
+	}
+	return nil
+}
+
+func (x *UpgradeHop) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type PlanUpgradeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// hops are ordered, the last one reaches the target. There are none if the target is already reached.
+	Hops          []*UpgradeHop `protobuf:"bytes,1,rep,name=hops,proto3" json:"hops,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlanUpgradeResponse) Reset() {
+	*x = PlanUpgradeResponse{}
+	mi := &file_api_version_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlanUpgradeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanUpgradeResponse) ProtoMessage() {}
+
+func (x *PlanUpgradeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_version_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanUpgradeResponse.ProtoReflect.Descriptor instead.
+func (*PlanUpgradeResponse) Descriptor() ([]byte, []int) {
+	return file_api_version_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PlanUpgradeResponse) GetHops() []*UpgradeHop {
+	if x != nil {
+		return x.Hops
+	}
+	return nil
+}
+
+// Version represents product version information.
+type Version struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ImagePath      string                 `protobuf:"bytes,1,opt,name=image_path,json=imagePath,proto3" json:"image_path,omitempty"`
+	ImageHash      string                 `protobuf:"bytes,2,opt,name=image_hash,json=imageHash,proto3" json:"image_hash,omitempty"`
+	ImageHashArm64 string                 `protobuf:"bytes,3,opt,name=image_hash_arm64,json=imageHashArm64,proto3" json:"image_hash_arm64,omitempty"`
+	Status         Status                 `protobuf:"varint,4,opt,name=status,proto3,enum=version.Status" json:"status,omitempty"`
+	Critical       bool                   `protobuf:"varint,5,opt,name=critical,proto3" json:"critical,omitempty"`
+	// supported_kube_versions is a semver constraint of Kubernetes versions the image supports,
+	// e.g. ">= 1.28, < 1.33". All versions are supported if it is empty.
+	SupportedKubeVersions string `protobuf:"bytes,6,opt,name=supported_kube_versions,json=supportedKubeVersions,proto3" json:"supported_kube_versions,omitempty"`
+	// supported_platforms lists platforms the image supports, e.g. openshift, eks or gke.
+	// All platforms are supported if it is empty.
+	SupportedPlatforms []string `protobuf:"bytes,7,rep,name=supported_platforms,json=supportedPlatforms,proto3" json:"supported_platforms,omitempty"`
+	// distributions holds images of the version for distributions of the product, e.g. community
+	// for PostgreSQL, used instead of image_path and the digests for clusters running that distribution.
+	Distributions map[string]*DistributionImage `protobuf:"bytes,8,rep,name=distributions,proto3" json:"distributions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// rollout recommends a recommended version to a growing percentage of clusters. Apply returns it
+	// as available to clusters out of the rollout, so they keep the previous recommendation.
+	Rollout *Rollout `protobuf:"bytes,9,opt,name=rollout,proto3" json:"rollout,omitempty"`
+	// withdrawn pulls a bad release. Apply never selects a withdrawn version for recommended or latest
+	// and returns it with a warning only to requests pinned to it, unless it is blocked.
+	Withdrawn *Withdrawal `protobuf:"bytes,10,opt,name=withdrawn,proto3" json:"withdrawn,omitempty"`
+	// publish_at embargoes the version: it is invisible until that time.
+	PublishAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=publish_at,json=publishAt,proto3" json:"publish_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Version) Reset() {
+	*x = Version{}
+	mi := &file_api_version_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Version) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Version) ProtoMessage() {}
+
+func (x *Version) ProtoReflect() protoreflect.Message {
+	mi := &file_api_version_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Version.ProtoReflect.Descriptor instead.
+func (*Version) Descriptor() ([]byte, []int) {
+	return file_api_version_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Version) GetImagePath() string {
+	if x != nil {
+		return x.ImagePath
+	}
+	return ""
+}
+
+func (x *Version) GetImageHash() string {
+	if x != nil {
+		return x.ImageHash
+	}
+	return ""
+}
+
+func (x *Version) GetImageHashArm64() string {
+	if x != nil {
+		return x.ImageHashArm64
+	}
+	return ""
+}
+
+func (x *Version) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_status_invalid
+}
+
+func (x *Version) GetCritical() bool {
+	if x != nil {
+		return x.Critical
+	}
+	return false
+}
+
+func (x *Version) GetSupportedKubeVersions() string {
+	if x != nil {
+		return x.SupportedKubeVersions
+	}
+	return ""
+}
+
+func (x *Version) GetSupportedPlatforms() []string {
+	if x != nil {
+		return x.SupportedPlatforms
+	}
+	return nil
+}
+
+func (x *Version) GetDistributions() map[string]*DistributionImage {
+	if x != nil {
+		return x.Distributions
+	}
+	return nil
+}
+
+func (x *Version) GetRollout() *Rollout {
+	if x != nil {
+		return x.Rollout
+	}
+	return nil
+}
+
+func (x *Version) GetWithdrawn() *Withdrawal {
+	if x != nil {
+		return x.Withdrawn
+	}
+	return nil
+}
+
+func (x *Version) GetPublishAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublishAt
+	}
+	return nil
+}
+
+// Withdrawal explains why a version was withdrawn.
+type Withdrawal struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Reason string                 `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	// advisory_url links the advisory describing the problem.
+	AdvisoryUrl string `protobuf:"bytes,2,opt,name=advisory_url,json=advisoryUrl,proto3" json:"advisory_url,omitempty"`
+	// replacement is the version of the component to use instead.
+	Replacement string `protobuf:"bytes,3,opt,name=replacement,proto3" json:"replacement,omitempty"`
+	// blocked forbids using the version: Apply fails with FailedPrecondition for requests pinned to it.
+	Blocked       bool `protobuf:"varint,4,opt,name=blocked,proto3" json:"blocked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Withdrawal) Reset() {
+	*x = Withdrawal{}
+	mi := &file_api_version_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Withdrawal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Withdrawal) ProtoMessage() {}
+
+func (x *Withdrawal) ProtoReflect() protoreflect.Message {
+	mi := &file_api_version_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Withdrawal.ProtoReflect.Descriptor instead.
+func (*Withdrawal) Descriptor() ([]byte, []int) {
+	return file_api_version_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Withdrawal) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Withdrawal) GetAdvisoryUrl() string {
+	if x != nil {
+		return x.AdvisoryUrl
+	}
+	return ""
+}
+
+func (x *Withdrawal) GetReplacement() string {
+	if x != nil {
+		return x.Replacement
+	}
+	return ""
+}
+
+func (x *Withdrawal) GetBlocked() bool {
+	if x != nil {
+		return x.Blocked
+	}
+	return false
+}
+
+// WithdrawnVersion is the error detail of Apply requests pinned to a blocked version.
+type WithdrawnVersion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Component     string                 `protobuf:"bytes,1,opt,name=component,proto3" json:"component,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Withdrawal    *Withdrawal            `protobuf:"bytes,3,opt,name=withdrawal,proto3" json:"withdrawal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WithdrawnVersion) Reset() {
+	*x = WithdrawnVersion{}
+	mi := &file_api_version_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WithdrawnVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WithdrawnVersion) ProtoMessage() {}
+
+func (x *WithdrawnVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_api_version_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WithdrawnVersion.ProtoReflect.Descriptor instead.
+func (*WithdrawnVersion) Descriptor() ([]byte, []int) {
+	return file_api_version_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WithdrawnVersion) GetComponent() string {
+	if x != nil {
+		return x.Component
+	}
+	return ""
+}
+
+func (x *WithdrawnVersion) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *WithdrawnVersion) GetWithdrawal() *Withdrawal {
+	if x != nil {
+		return x.Withdrawal
+	}
+	return nil
+}
+
+// Rollout stages the recommendation of a version.
+type Rollout struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// stages in order of their start times. The version is recommended to no cluster before the first one.
+	Stages []*RolloutStage `protobuf:"bytes,1,rep,name=stages,proto3" json:"stages,omitempty"`
+	// paused_at pauses the rollout at the stage reached at that time.
+	PausedAt      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=paused_at,json=pausedAt,proto3" json:"paused_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rollout) Reset() {
+	*x = Rollout{}
+	mi := &file_api_version_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rollout) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rollout) ProtoMessage() {}
+
+func (x *Rollout) ProtoReflect() protoreflect.Message {
+	mi := &file_api_version_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rollout.ProtoReflect.Descriptor instead.
+func (*Rollout) Descriptor() ([]byte, []int) {
+	return file_api_version_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Rollout) GetStages() []*RolloutStage {
+	if x != nil {
+		return x.Stages
+	}
+	return nil
+}
+
+func (x *Rollout) GetPausedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PausedAt
+	}
+	return nil
+}
+
+// RolloutStage recommends a version to a percentage of clusters from its start time.
+type RolloutStage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Percent       uint32                 `protobuf:"varint,1,opt,name=percent,proto3" json:"percent,omitempty"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RolloutStage) Reset() {
+	*x = RolloutStage{}
+	mi := &file_api_version_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RolloutStage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RolloutStage) ProtoMessage() {}
+
+func (x *RolloutStage) ProtoReflect() protoreflect.Message {
+	mi := &file_api_version_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RolloutStage.ProtoReflect.Descriptor instead.
+func (*RolloutStage) Descriptor() ([]byte, []int) {
+	return file_api_version_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RolloutStage) GetPercent() uint32 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+func (x *RolloutStage) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+// DistributionImage is the image of a version for a distribution of the product.
+type DistributionImage struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ImagePath      string                 `protobuf:"bytes,1,opt,name=image_path,json=imagePath,proto3" json:"image_path,omitempty"`
+	ImageHash      string                 `protobuf:"bytes,2,opt,name=image_hash,json=imageHash,proto3" json:"image_hash,omitempty"`
+	ImageHashArm64 string                 `protobuf:"bytes,3,opt,name=image_hash_arm64,json=imageHashArm64,proto3" json:"image_hash_arm64,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DistributionImage) Reset() {
+	*x = DistributionImage{}
+	mi := &file_api_version_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DistributionImage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DistributionImage) ProtoMessage() {}
+
+func (x *DistributionImage) ProtoReflect() protoreflect.Message {
+	mi := &file_api_version_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DistributionImage.ProtoReflect.Descriptor instead.
+func (*DistributionImage) Descriptor() ([]byte, []int) {
+	return file_api_version_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DistributionImage) GetImagePath() string {
 	if x != nil {
 		return x.ImagePath
 	}
 	return ""
 }
 
-func (x *Version) GetImageHash() string {
+func (x *DistributionImage) GetImageHash() string {
 	if x != nil {
 		return x.ImageHash
 	}
 	return ""
 }
 
-func (x *Version) GetImageHashArm64() string {
+func (x *DistributionImage) GetImageHashArm64() string {
 	if x != nil {
 		return x.ImageHashArm64
 	}
 	return ""
 }
 
-func (x *Version) GetStatus() Status {
-	if x != nil {
-		return x.Status
-	}
-	return Status_status_invalid
-}
-
-func (x *Version) GetCritical() bool {
-	if x != nil {
-		return x.Critical
-	}
-	return false
-}
-
 // Version represents product version information.
 type VersionV2 struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
@@ -1021,7 +1658,7 @@ type VersionV2 struct {
 
 func (x *VersionV2) Reset() {
 	*x = VersionV2{}
-	mi := &file_api_version_proto_msgTypes[5]
+	mi := &file_api_version_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1033,7 +1670,7 @@ func (x *VersionV2) String() string {
 func (*VersionV2) ProtoMessage() {}
 
 func (x *VersionV2) ProtoReflect() protoreflect.Message {
-	mi := &file_api_version_proto_msgTypes[5]
+	mi := &file_api_version_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1046,7 +1683,7 @@ func (x *VersionV2) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VersionV2.ProtoReflect.Descriptor instead.
 func (*VersionV2) Descriptor() ([]byte, []int) {
-	return file_api_version_proto_rawDescGZIP(), []int{5}
+	return file_api_version_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *VersionV2) GetImagePath() string {
@@ -1123,13 +1760,16 @@ type VersionMatrix struct {
 	Postgis             map[string]*Version    `protobuf:"bytes,27,rep,name=postgis,proto3" json:"postgis,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	BinlogServer        map[string]*Version    `protobuf:"bytes,28,rep,name=binlog_server,json=binlogServer,proto3" json:"binlog_server,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	Pgupgrade           map[string]*Version    `protobuf:"bytes,29,rep,name=pgupgrade,proto3" json:"pgupgrade,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
+	// components holds versions of components which do not have their own field,
+	// e.g. sidecars declared only in sources, keyed by component name.
+	Components    map[string]*ComponentVersions `protobuf:"bytes,30,rep,name=components,proto3" json:"components,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *VersionMatrix) Reset() {
 	*x = VersionMatrix{}
-	mi := &file_api_version_proto_msgTypes[6]
+	mi := &file_api_version_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1141,7 +1781,7 @@ func (x *VersionMatrix) String() string {
 func (*VersionMatrix) ProtoMessage() {}
 
 func (x *VersionMatrix) ProtoReflect() protoreflect.Message {
-	mi := &file_api_version_proto_msgTypes[6]
+	mi := &file_api_version_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1154,7 +1794,7 @@ func (x *VersionMatrix) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VersionMatrix.ProtoReflect.Descriptor instead.
 func (*VersionMatrix) Descriptor() ([]byte, []int) {
-	return file_api_version_proto_rawDescGZIP(), []int{6}
+	return file_api_version_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *VersionMatrix) GetMongod() map[string]*Version {
@@ -1360,19 +2000,78 @@ func (x *VersionMatrix) GetPgupgrade() map[string]*Version {
 	return nil
 }
 
+func (x *VersionMatrix) GetComponents() map[string]*ComponentVersions {
+	if x != nil {
+		return x.Components
+	}
+	return nil
+}
+
+// ComponentVersions represents versions of a single component.
+type ComponentVersions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Versions      map[string]*Version    `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComponentVersions) Reset() {
+	*x = ComponentVersions{}
+	mi := &file_api_version_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComponentVersions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComponentVersions) ProtoMessage() {}
+
+func (x *ComponentVersions) ProtoReflect() protoreflect.Message {
+	mi := &file_api_version_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComponentVersions.ProtoReflect.Descriptor instead.
+func (*ComponentVersions) Descriptor() ([]byte, []int) {
+	return file_api_version_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ComponentVersions) GetVersions() map[string]*Version {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
 // OperatorVersion represents operator version.
 type OperatorVersion struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Product       string                 `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
-	Operator      string                 `protobuf:"bytes,2,opt,name=operator,proto3" json:"operator,omitempty"`
-	Matrix        *VersionMatrix         `protobuf:"bytes,3,opt,name=matrix,proto3" json:"matrix,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Product  string                 `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	Operator string                 `protobuf:"bytes,2,opt,name=operator,proto3" json:"operator,omitempty"`
+	Matrix   *VersionMatrix         `protobuf:"bytes,3,opt,name=matrix,proto3" json:"matrix,omitempty"`
+	// supported_kube_versions is a semver constraint of Kubernetes versions the operator supports.
+	// All versions are supported if it is empty.
+	SupportedKubeVersions string `protobuf:"bytes,4,opt,name=supported_kube_versions,json=supportedKubeVersions,proto3" json:"supported_kube_versions,omitempty"`
+	// supported_platforms lists platforms the operator supports. All platforms are supported if it is empty.
+	SupportedPlatforms []string `protobuf:"bytes,5,rep,name=supported_platforms,json=supportedPlatforms,proto3" json:"supported_platforms,omitempty"`
+	// publish_at embargoes the operator version: it is invisible until that time.
+	PublishAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=publish_at,json=publishAt,proto3" json:"publish_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *OperatorVersion) Reset() {
 	*x = OperatorVersion{}
-	mi := &file_api_version_proto_msgTypes[7]
+	mi := &file_api_version_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1384,7 +2083,7 @@ func (x *OperatorVersion) String() string {
 func (*OperatorVersion) ProtoMessage() {}
 
 func (x *OperatorVersion) ProtoReflect() protoreflect.Message {
-	mi := &file_api_version_proto_msgTypes[7]
+	mi := &file_api_version_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1397,7 +2096,7 @@ func (x *OperatorVersion) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OperatorVersion.ProtoReflect.Descriptor instead.
 func (*OperatorVersion) Descriptor() ([]byte, []int) {
-	return file_api_version_proto_rawDescGZIP(), []int{7}
+	return file_api_version_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *OperatorVersion) GetProduct() string {
@@ -1421,16 +2120,41 @@ func (x *OperatorVersion) GetMatrix() *VersionMatrix {
 	return nil
 }
 
+func (x *OperatorVersion) GetSupportedKubeVersions() string {
+	if x != nil {
+		return x.SupportedKubeVersions
+	}
+	return ""
+}
+
+func (x *OperatorVersion) GetSupportedPlatforms() []string {
+	if x != nil {
+		return x.SupportedPlatforms
+	}
+	return nil
+}
+
+func (x *OperatorVersion) GetPublishAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublishAt
+	}
+	return nil
+}
+
 type VersionResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Versions      []*OperatorVersion     `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Versions []*OperatorVersion     `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+	// explanation describes how Apply selected the versions of every component if requested.
+	Explanation []*ComponentExplanation `protobuf:"bytes,2,rep,name=explanation,proto3" json:"explanation,omitempty"`
+	// warnings describe requested versions Apply could not honor, e.g. incompatible pinned component versions.
+	Warnings      []string `protobuf:"bytes,3,rep,name=warnings,proto3" json:"warnings,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *VersionResponse) Reset() {
 	*x = VersionResponse{}
-	mi := &file_api_version_proto_msgTypes[8]
+	mi := &file_api_version_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1442,7 +2166,7 @@ func (x *VersionResponse) String() string {
 func (*VersionResponse) ProtoMessage() {}
 
 func (x *VersionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_version_proto_msgTypes[8]
+	mi := &file_api_version_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1455,7 +2179,7 @@ func (x *VersionResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VersionResponse.ProtoReflect.Descriptor instead.
 func (*VersionResponse) Descriptor() ([]byte, []int) {
-	return file_api_version_proto_rawDescGZIP(), []int{8}
+	return file_api_version_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *VersionResponse) GetVersions() []*OperatorVersion {
@@ -1465,6 +2189,145 @@ func (x *VersionResponse) GetVersions() []*OperatorVersion {
 	return nil
 }
 
+func (x *VersionResponse) GetExplanation() []*ComponentExplanation {
+	if x != nil {
+		return x.Explanation
+	}
+	return nil
+}
+
+func (x *VersionResponse) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+// ComponentExplanation explains how Apply selected the versions of a component.
+type ComponentExplanation struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Component string                 `protobuf:"bytes,1,opt,name=component,proto3" json:"component,omitempty"`
+	// candidates are all versions of the component in the source sorted from the highest one.
+	Candidates []*CandidateExplanation `protobuf:"bytes,2,rep,name=candidates,proto3" json:"candidates,omitempty"`
+	// selected lists the versions returned for the component.
+	Selected      []string `protobuf:"bytes,3,rep,name=selected,proto3" json:"selected,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComponentExplanation) Reset() {
+	*x = ComponentExplanation{}
+	mi := &file_api_version_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComponentExplanation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComponentExplanation) ProtoMessage() {}
+
+func (x *ComponentExplanation) ProtoReflect() protoreflect.Message {
+	mi := &file_api_version_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComponentExplanation.ProtoReflect.Descriptor instead.
+func (*ComponentExplanation) Descriptor() ([]byte, []int) {
+	return file_api_version_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ComponentExplanation) GetComponent() string {
+	if x != nil {
+		return x.Component
+	}
+	return ""
+}
+
+func (x *ComponentExplanation) GetCandidates() []*CandidateExplanation {
+	if x != nil {
+		return x.Candidates
+	}
+	return nil
+}
+
+func (x *ComponentExplanation) GetSelected() []string {
+	if x != nil {
+		return x.Selected
+	}
+	return nil
+}
+
+// CandidateExplanation explains why a version of a component was selected or dropped.
+type CandidateExplanation struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Version  string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Selected bool                   `protobuf:"varint,2,opt,name=selected,proto3" json:"selected,omitempty"`
+	// reason is why the version was dropped, e.g. its dependency rule is false.
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CandidateExplanation) Reset() {
+	*x = CandidateExplanation{}
+	mi := &file_api_version_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CandidateExplanation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CandidateExplanation) ProtoMessage() {}
+
+func (x *CandidateExplanation) ProtoReflect() protoreflect.Message {
+	mi := &file_api_version_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CandidateExplanation.ProtoReflect.Descriptor instead.
+func (*CandidateExplanation) Descriptor() ([]byte, []int) {
+	return file_api_version_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CandidateExplanation) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *CandidateExplanation) GetSelected() bool {
+	if x != nil {
+		return x.Selected
+	}
+	return false
+}
+
+func (x *CandidateExplanation) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
 type OperatorResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Versions      []*OperatorVersion     `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
@@ -1474,7 +2337,7 @@ type OperatorResponse struct {
 
 func (x *OperatorResponse) Reset() {
 	*x = OperatorResponse{}
-	mi := &file_api_version_proto_msgTypes[9]
+	mi := &file_api_version_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1486,7 +2349,7 @@ func (x *OperatorResponse) String() string {
 func (*OperatorResponse) ProtoMessage() {}
 
 func (x *OperatorResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_version_proto_msgTypes[9]
+	mi := &file_api_version_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1499,7 +2362,7 @@ func (x *OperatorResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OperatorResponse.ProtoReflect.Descriptor instead.
 func (*OperatorResponse) Descriptor() ([]byte, []int) {
-	return file_api_version_proto_rawDescGZIP(), []int{9}
+	return file_api_version_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *OperatorResponse) GetVersions() []*OperatorVersion {
@@ -1510,15 +2373,18 @@ func (x *OperatorResponse) GetVersions() []*OperatorVersion {
 }
 
 type ProductResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Versions      []*OperatorVersion     `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// versions are sorted by operator version in ascending order.
+	Versions []*OperatorVersion `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+	// next_page_token is the token to request the next page. It is empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ProductResponse) Reset() {
 	*x = ProductResponse{}
-	mi := &file_api_version_proto_msgTypes[10]
+	mi := &file_api_version_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1530,7 +2396,7 @@ func (x *ProductResponse) String() string {
 func (*ProductResponse) ProtoMessage() {}
 
 func (x *ProductResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_version_proto_msgTypes[10]
+	mi := &file_api_version_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1543,7 +2409,7 @@ func (x *ProductResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProductResponse.ProtoReflect.Descriptor instead.
 func (*ProductResponse) Descriptor() ([]byte, []int) {
-	return file_api_version_proto_rawDescGZIP(), []int{10}
+	return file_api_version_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *ProductResponse) GetVersions() []*OperatorVersion {
@@ -1553,6 +2419,13 @@ func (x *ProductResponse) GetVersions() []*OperatorVersion {
 	return nil
 }
 
+func (x *ProductResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 // MetadataVersion represents metadata for a given version.
 type MetadataVersion struct {
 	state   protoimpl.MessageState `protogen:"open.v1"`
@@ -1560,14 +2433,16 @@ type MetadataVersion struct {
 	// Recommended is the recommended version for a given component.
 	Recommended map[string]string `protobuf:"bytes,2,rep,name=recommended,proto3" json:"recommended,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	// Supported holds semver constraint per component, such as ">= 1.0, < 1.4".
-	Supported     map[string]string `protobuf:"bytes,3,rep,name=supported,proto3" json:"supported,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Supported map[string]string `protobuf:"bytes,3,rep,name=supported,proto3" json:"supported,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// publish_at embargoes the version: it is invisible until that time.
+	PublishAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=publish_at,json=publishAt,proto3" json:"publish_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *MetadataVersion) Reset() {
 	*x = MetadataVersion{}
-	mi := &file_api_version_proto_msgTypes[11]
+	mi := &file_api_version_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1579,7 +2454,7 @@ func (x *MetadataVersion) String() string {
 func (*MetadataVersion) ProtoMessage() {}
 
 func (x *MetadataVersion) ProtoReflect() protoreflect.Message {
-	mi := &file_api_version_proto_msgTypes[11]
+	mi := &file_api_version_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1592,7 +2467,7 @@ func (x *MetadataVersion) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MetadataVersion.ProtoReflect.Descriptor instead.
 func (*MetadataVersion) Descriptor() ([]byte, []int) {
-	return file_api_version_proto_rawDescGZIP(), []int{11}
+	return file_api_version_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *MetadataVersion) GetVersion() string {
@@ -1616,6 +2491,13 @@ func (x *MetadataVersion) GetSupported() map[string]string {
 	return nil
 }
 
+func (x *MetadataVersion) GetPublishAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublishAt
+	}
+	return nil
+}
+
 // MetadataV2Version represents metadata for a given version with additional fields.
 type MetadataV2Version struct {
 	state   protoimpl.MessageState `protogen:"open.v1"`
@@ -1625,14 +2507,16 @@ type MetadataV2Version struct {
 	// Supported holds semver constraint per component, such as ">= 1.0, < 1.4".
 	Supported map[string]string `protobuf:"bytes,3,rep,name=supported,proto3" json:"supported,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	// ImageInfo holds information about the docker image for this version.
-	ImageInfo     *VersionV2 `protobuf:"bytes,4,opt,name=image_info,json=imageInfo,proto3" json:"image_info,omitempty"`
+	ImageInfo *VersionV2 `protobuf:"bytes,4,opt,name=image_info,json=imageInfo,proto3" json:"image_info,omitempty"`
+	// publish_at embargoes the version: it is invisible until that time.
+	PublishAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=publish_at,json=publishAt,proto3" json:"publish_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *MetadataV2Version) Reset() {
 	*x = MetadataV2Version{}
-	mi := &file_api_version_proto_msgTypes[12]
+	mi := &file_api_version_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1644,7 +2528,7 @@ func (x *MetadataV2Version) String() string {
 func (*MetadataV2Version) ProtoMessage() {}
 
 func (x *MetadataV2Version) ProtoReflect() protoreflect.Message {
-	mi := &file_api_version_proto_msgTypes[12]
+	mi := &file_api_version_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1657,7 +2541,7 @@ func (x *MetadataV2Version) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MetadataV2Version.ProtoReflect.Descriptor instead.
 func (*MetadataV2Version) Descriptor() ([]byte, []int) {
-	return file_api_version_proto_rawDescGZIP(), []int{12}
+	return file_api_version_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *MetadataV2Version) GetVersion() string {
@@ -1688,6 +2572,13 @@ func (x *MetadataV2Version) GetImageInfo() *VersionV2 {
 	return nil
 }
 
+func (x *MetadataV2Version) GetPublishAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublishAt
+	}
+	return nil
+}
+
 type MetadataResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Versions      []*MetadataVersion     `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
@@ -1697,7 +2588,7 @@ type MetadataResponse struct {
 
 func (x *MetadataResponse) Reset() {
 	*x = MetadataResponse{}
-	mi := &file_api_version_proto_msgTypes[13]
+	mi := &file_api_version_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1709,7 +2600,7 @@ func (x *MetadataResponse) String() string {
 func (*MetadataResponse) ProtoMessage() {}
 
 func (x *MetadataResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_version_proto_msgTypes[13]
+	mi := &file_api_version_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1722,7 +2613,7 @@ func (x *MetadataResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MetadataResponse.ProtoReflect.Descriptor instead.
 func (*MetadataResponse) Descriptor() ([]byte, []int) {
-	return file_api_version_proto_rawDescGZIP(), []int{13}
+	return file_api_version_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *MetadataResponse) GetVersions() []*MetadataVersion {
@@ -1741,7 +2632,7 @@ type MetadataV2Response struct {
 
 func (x *MetadataV2Response) Reset() {
 	*x = MetadataV2Response{}
-	mi := &file_api_version_proto_msgTypes[14]
+	mi := &file_api_version_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1753,7 +2644,7 @@ func (x *MetadataV2Response) String() string {
 func (*MetadataV2Response) ProtoMessage() {}
 
 func (x *MetadataV2Response) ProtoReflect() protoreflect.Message {
-	mi := &file_api_version_proto_msgTypes[14]
+	mi := &file_api_version_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1766,7 +2657,7 @@ func (x *MetadataV2Response) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MetadataV2Response.ProtoReflect.Descriptor instead.
 func (*MetadataV2Response) Descriptor() ([]byte, []int) {
-	return file_api_version_proto_rawDescGZIP(), []int{14}
+	return file_api_version_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *MetadataV2Response) GetVersions() []*MetadataV2Version {
@@ -1788,7 +2679,7 @@ type GetReleaseNotesRequest struct {
 
 func (x *GetReleaseNotesRequest) Reset() {
 	*x = GetReleaseNotesRequest{}
-	mi := &file_api_version_proto_msgTypes[15]
+	mi := &file_api_version_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1800,7 +2691,7 @@ func (x *GetReleaseNotesRequest) String() string {
 func (*GetReleaseNotesRequest) ProtoMessage() {}
 
 func (x *GetReleaseNotesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_version_proto_msgTypes[15]
+	mi := &file_api_version_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1813,7 +2704,7 @@ func (x *GetReleaseNotesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetReleaseNotesRequest.ProtoReflect.Descriptor instead.
 func (*GetReleaseNotesRequest) Descriptor() ([]byte, []int) {
-	return file_api_version_proto_rawDescGZIP(), []int{15}
+	return file_api_version_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *GetReleaseNotesRequest) GetProduct() string {
@@ -1843,7 +2734,7 @@ type GetReleaseNotesResponse struct {
 
 func (x *GetReleaseNotesResponse) Reset() {
 	*x = GetReleaseNotesResponse{}
-	mi := &file_api_version_proto_msgTypes[16]
+	mi := &file_api_version_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1855,7 +2746,7 @@ func (x *GetReleaseNotesResponse) String() string {
 func (*GetReleaseNotesResponse) ProtoMessage() {}
 
 func (x *GetReleaseNotesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_version_proto_msgTypes[16]
+	mi := &file_api_version_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1868,7 +2759,7 @@ func (x *GetReleaseNotesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetReleaseNotesResponse.ProtoReflect.Descriptor instead.
 func (*GetReleaseNotesResponse) Descriptor() ([]byte, []int) {
-	return file_api_version_proto_rawDescGZIP(), []int{16}
+	return file_api_version_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *GetReleaseNotesResponse) GetProduct() string {
@@ -1896,12 +2787,11 @@ var File_api_version_proto protoreflect.FileDescriptor
 
 const file_api_version_proto_rawDesc = "" +
 	"\n" +
-	"\x11api/version.proto\x12\aversion\x1a\x1cgoogle/api/annotations.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xee\n" +
-	"\n" +
+	"\x11api/version.proto\x12\aversion\x1a\x1cgoogle/api/annotations.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x85\x13\n" +
 	"\fApplyRequest\x12\x18\n" +
-	"\aproduct\x18\x01 \x01(\tR\aproduct\x12)\n" +
-	"\x10operator_version\x18\x02 \x01(\tR\x0foperatorVersion\x12\x14\n" +
-	"\x05apply\x18\x03 \x01(\tR\x05apply\x12)\n" +
+	"\aproduct\x18\x01 \x01(\tR\aproduct\x12\xe8\x01\n" +
+	"\x10operator_version\x18\x02 \x01(\tB\xbc\x01\x92A\xb8\x012\xb5\x01Operator version: an exact version, latest, major.minor for the highest patch release or a semver constraint. The resolved version is returned in the operator field of the response.R\x0foperatorVersion\x12\x8a\x02\n" +
+	"\x05apply\x18\x03 \x01(\tB\xf3\x01\x92A\xef\x012\xec\x01Version to apply: an exact version, a range such as ~8.0.36 or ^16 optionally followed by -latest, latest, recommended or never. major-latest and major-recommended select the version on the next line database_version can be upgraded to.R\x05apply\x12)\n" +
 	"\x10database_version\x18\x04 \x01(\tR\x0fdatabaseVersion\x12!\n" +
 	"\fkube_version\x18\x05 \x01(\tR\vkubeVersion\x12\x1a\n" +
 	"\bplatform\x18\x06 \x01(\tR\bplatform\x12\x1f\n" +
@@ -1933,12 +2823,14 @@ const file_api_version_proto_rawDesc = "" +
 	"\x17role_management_enabled\x18\x1b \x01(\bR\x15roleManagementEnabled\x12\x1f\n" +
 	"\vmcs_enabled\x18\x1c \x01(\bR\n" +
 	"mcsEnabled\x128\n" +
-	"\x18volume_expansion_enabled\x18\x1d \x01(\bR\x16volumeExpansionEnabled\x12\xaa\x01\n" +
-	"\fdistribution\x18\x1e \x01(\tB\x85\x01\x92A\x81\x012fPostgreSQL distribution running in the cluster. Empty string means unknown (e.g. no running pods yet).\xf2\x02\x00\xf2\x02\apercona\xf2\x02\tcommunityR\fdistribution\"\xdb\n" +
-	"\n" +
+	"\x18volume_expansion_enabled\x18\x1d \x01(\bR\x16volumeExpansionEnabled\x12\xf4\x01\n" +
+	"\fdistribution\x18\x1e \x01(\tB\xcf\x01\x92A\xcb\x012\xaf\x01PostgreSQL distribution running in the cluster. Versions with distribution images return the image of this distribution. Empty string means unknown (e.g. no running pods yet).\xf2\x02\x00\xf2\x02\apercona\xf2\x02\tcommunityR\fdistribution\x12\x8f\x01\n" +
+	"\bregistry\x18\x1f \x01(\tBs\x92Ap2nName of the registry rewrite rules to apply to image paths. Can also be set with the X-Registry-Mirror header.R\bregistry\x12\xf7\x01\n" +
+	"\x04arch\x18  \x01(\tB\xe2\x01\x92A\xde\x012\xc0\x01CPU architecture of the cluster. Only versions with an image digest for it, or both digests for multi, are selected and image_hash holds the digest for it. Empty string means any architecture.\xf2\x02\x00\xf2\x02\x05amd64\xf2\x02\x05arm64\xf2\x02\x05multiR\x04arch\x12\x87\x01\n" +
+	"\aexplain\x18! \x01(\bBm\x92Aj2hAdd the explanation of the selected versions to the response. Can also be set with the X-Explain header.R\aexplain\"\xf7\r\n" +
 	"\x0fOperatorRequest\x12\x18\n" +
-	"\aproduct\x18\x01 \x01(\tR\aproduct\x12)\n" +
-	"\x10operator_version\x18\x02 \x01(\tR\x0foperatorVersion\x12)\n" +
+	"\aproduct\x18\x01 \x01(\tR\aproduct\x12\xe8\x01\n" +
+	"\x10operator_version\x18\x02 \x01(\tB\xbc\x01\x92A\xb8\x012\xb5\x01Operator version: an exact version, latest, major.minor for the highest patch release or a semver constraint. The resolved version is returned in the operator field of the response.R\x0foperatorVersion\x12)\n" +
 	"\x10database_version\x18\x04 \x01(\tR\x0fdatabaseVersion\x12!\n" +
 	"\fkube_version\x18\x05 \x01(\tR\vkubeVersion\x12\x1a\n" +
 	"\bplatform\x18\x06 \x01(\tR\bplatform\x12\x1f\n" +
@@ -1970,11 +2862,12 @@ const file_api_version_proto_rawDesc = "" +
 	"\x17role_management_enabled\x18\x1b \x01(\bR\x15roleManagementEnabled\x12\x1f\n" +
 	"\vmcs_enabled\x18\x1c \x01(\bR\n" +
 	"mcsEnabled\x128\n" +
-	"\x18volume_expansion_enabled\x18\x1d \x01(\bR\x16volumeExpansionEnabled\x12\xaa\x01\n" +
-	"\fdistribution\x18\x1e \x01(\tB\x85\x01\x92A\x81\x012fPostgreSQL distribution running in the cluster. Empty string means unknown (e.g. no running pods yet).\xf2\x02\x00\xf2\x02\apercona\xf2\x02\tcommunityR\fdistribution\"\xaf\n" +
-	"\n" +
+	"\x18volume_expansion_enabled\x18\x1d \x01(\bR\x16volumeExpansionEnabled\x12\xf4\x01\n" +
+	"\fdistribution\x18\x1e \x01(\tB\xcf\x01\x92A\xcb\x012\xaf\x01PostgreSQL distribution running in the cluster. Versions with distribution images return the image of this distribution. Empty string means unknown (e.g. no running pods yet).\xf2\x02\x00\xf2\x02\apercona\xf2\x02\tcommunityR\fdistribution\x12\x8f\x01\n" +
+	"\bregistry\x18\x1f \x01(\tBs\x92Ap2nName of the registry rewrite rules to apply to image paths. Can also be set with the X-Registry-Mirror header.R\bregistry\"\xf2\f\n" +
 	"\x0eProductRequest\x12\x18\n" +
 	"\aproduct\x18\x01 \x01(\tR\aproduct\x12)\n" +
+	"\x10operator_version\x18\x02 \x01(\tR\x0foperatorVersion\x12)\n" +
 	"\x10database_version\x18\x04 \x01(\tR\x0fdatabaseVersion\x12!\n" +
 	"\fkube_version\x18\x05 \x01(\tR\vkubeVersion\x12\x1a\n" +
 	"\bplatform\x18\x06 \x01(\tR\bplatform\x12\x1f\n" +
@@ -2006,10 +2899,29 @@ const file_api_version_proto_rawDesc = "" +
 	"\x17role_management_enabled\x18\x1b \x01(\bR\x15roleManagementEnabled\x12\x1f\n" +
 	"\vmcs_enabled\x18\x1c \x01(\bR\n" +
 	"mcsEnabled\x128\n" +
-	"\x18volume_expansion_enabled\x18\x1d \x01(\bR\x16volumeExpansionEnabled\x12\xaa\x01\n" +
-	"\fdistribution\x18\x1e \x01(\tB\x85\x01\x92A\x81\x012fPostgreSQL distribution running in the cluster. Empty string means unknown (e.g. no running pods yet).\xf2\x02\x00\xf2\x02\apercona\xf2\x02\tcommunityR\fdistribution\"+\n" +
+	"\x18volume_expansion_enabled\x18\x1d \x01(\bR\x16volumeExpansionEnabled\x12\xf4\x01\n" +
+	"\fdistribution\x18\x1e \x01(\tB\xcf\x01\x92A\xcb\x012\xaf\x01PostgreSQL distribution running in the cluster. Versions with distribution images return the image of this distribution. Empty string means unknown (e.g. no running pods yet).\xf2\x02\x00\xf2\x02\apercona\xf2\x02\tcommunityR\fdistribution\x12\x8f\x01\n" +
+	"\bregistry\x18\x1f \x01(\tBs\x92Ap2nName of the registry rewrite rules to apply to image paths. Can also be set with the X-Registry-Mirror header.R\bregistry\x12\x1b\n" +
+	"\tpage_size\x18  \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18! \x01(\tR\tpageToken\"+\n" +
 	"\x0fMetadataRequest\x12\x18\n" +
-	"\aproduct\x18\x01 \x01(\tR\aproduct\"\xb6\x01\n" +
+	"\aproduct\x18\x01 \x01(\tR\aproduct\"\xcc\x05\n" +
+	"\x12PlanUpgradeRequest\x12\x18\n" +
+	"\aproduct\x18\x01 \x01(\tR\aproduct\x12)\n" +
+	"\x10operator_version\x18\x02 \x01(\tR\x0foperatorVersion\x12)\n" +
+	"\x10database_version\x18\x03 \x01(\tR\x0fdatabaseVersion\x12\xde\x01\n" +
+	"\x17target_operator_version\x18\x04 \x01(\tB\xa5\x01\x92A\xa1\x012\x9e\x01Target operator version: an exact version, latest, major.minor for the highest patch release or a semver constraint. Defaults to the running operator version.R\x15targetOperatorVersion\x12\xd2\x01\n" +
+	"\x17target_database_version\x18\x05 \x01(\tB\x99\x01\x92A\x95\x012\x92\x01Target database version: an exact version or a version line, e.g. 8.4, for its recommended version. The database is not upgraded if it is not set.R\x15targetDatabaseVersion\x12\x8f\x01\n" +
+	"\bregistry\x18\x06 \x01(\tBs\x92Ap2nName of the registry rewrite rules to apply to image paths. Can also be set with the X-Registry-Mirror header.R\bregistry\"\xaa\x01\n" +
+	"\n" +
+	"UpgradeHop\x12)\n" +
+	"\x10operator_version\x18\x01 \x01(\tR\x0foperatorVersion\x12)\n" +
+	"\x10database_version\x18\x02 \x01(\tR\x0fdatabaseVersion\x12.\n" +
+	"\x06matrix\x18\x03 \x01(\v2\x16.version.VersionMatrixR\x06matrix\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\">\n" +
+	"\x13PlanUpgradeResponse\x12'\n" +
+	"\x04hops\x18\x01 \x03(\v2\x13.version.UpgradeHopR\x04hops\"\xe2\x04\n" +
 	"\aVersion\x12\x1d\n" +
 	"\n" +
 	"image_path\x18\x01 \x01(\tR\timagePath\x12\x1d\n" +
@@ -2017,7 +2929,42 @@ const file_api_version_proto_rawDesc = "" +
 	"image_hash\x18\x02 \x01(\tR\timageHash\x12(\n" +
 	"\x10image_hash_arm64\x18\x03 \x01(\tR\x0eimageHashArm64\x12'\n" +
 	"\x06status\x18\x04 \x01(\x0e2\x0f.version.StatusR\x06status\x12\x1a\n" +
-	"\bcritical\x18\x05 \x01(\bR\bcritical\"\x8c\x02\n" +
+	"\bcritical\x18\x05 \x01(\bR\bcritical\x126\n" +
+	"\x17supported_kube_versions\x18\x06 \x01(\tR\x15supportedKubeVersions\x12/\n" +
+	"\x13supported_platforms\x18\a \x03(\tR\x12supportedPlatforms\x12I\n" +
+	"\rdistributions\x18\b \x03(\v2#.version.Version.DistributionsEntryR\rdistributions\x12*\n" +
+	"\arollout\x18\t \x01(\v2\x10.version.RolloutR\arollout\x121\n" +
+	"\twithdrawn\x18\n" +
+	" \x01(\v2\x13.version.WithdrawalR\twithdrawn\x129\n" +
+	"\n" +
+	"publish_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tpublishAt\x1a\\\n" +
+	"\x12DistributionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
+	"\x05value\x18\x02 \x01(\v2\x1a.version.DistributionImageR\x05value:\x028\x01\"\x83\x01\n" +
+	"\n" +
+	"Withdrawal\x12\x16\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\x12!\n" +
+	"\fadvisory_url\x18\x02 \x01(\tR\vadvisoryUrl\x12 \n" +
+	"\vreplacement\x18\x03 \x01(\tR\vreplacement\x12\x18\n" +
+	"\ablocked\x18\x04 \x01(\bR\ablocked\"\x7f\n" +
+	"\x10WithdrawnVersion\x12\x1c\n" +
+	"\tcomponent\x18\x01 \x01(\tR\tcomponent\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x123\n" +
+	"\n" +
+	"withdrawal\x18\x03 \x01(\v2\x13.version.WithdrawalR\n" +
+	"withdrawal\"q\n" +
+	"\aRollout\x12-\n" +
+	"\x06stages\x18\x01 \x03(\v2\x15.version.RolloutStageR\x06stages\x127\n" +
+	"\tpaused_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\bpausedAt\"Z\n" +
+	"\fRolloutStage\x12\x18\n" +
+	"\apercent\x18\x01 \x01(\rR\apercent\x120\n" +
+	"\x05start\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\"{\n" +
+	"\x11DistributionImage\x12\x1d\n" +
+	"\n" +
+	"image_path\x18\x01 \x01(\tR\timagePath\x12\x1d\n" +
+	"\n" +
+	"image_hash\x18\x02 \x01(\tR\timageHash\x12(\n" +
+	"\x10image_hash_arm64\x18\x03 \x01(\tR\x0eimageHashArm64\"\x8c\x02\n" +
 	"\tVersionV2\x12\x1d\n" +
 	"\n" +
 	"image_path\x18\x01 \x01(\tR\timagePath\x12\x1d\n" +
@@ -2026,7 +2973,7 @@ const file_api_version_proto_rawDesc = "" +
 	"\x10image_hash_arm64\x18\x03 \x01(\tR\x0eimageHashArm64\x12R\n" +
 	"\x17image_release_timestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x15imageReleaseTimestamp\x12'\n" +
 	"\x06status\x18\x04 \x01(\x0e2\x0f.version.StatusR\x06status\x12\x1a\n" +
-	"\bcritical\x18\x05 \x01(\bR\bcritical\"\x81#\n" +
+	"\bcritical\x18\x05 \x01(\bR\bcritical\"\xa4$\n" +
 	"\rVersionMatrix\x12:\n" +
 	"\x06mongod\x18\x01 \x03(\v2\".version.VersionMatrix.MongodEntryR\x06mongod\x121\n" +
 	"\x03pxc\x18\x02 \x03(\v2\x1f.version.VersionMatrix.PxcEntryR\x03pxc\x121\n" +
@@ -2063,7 +3010,10 @@ const file_api_version_proto_rawDesc = "" +
 	"\atoolkit\x18\x1a \x03(\v2#.version.VersionMatrix.ToolkitEntryR\atoolkit\x12=\n" +
 	"\apostgis\x18\x1b \x03(\v2#.version.VersionMatrix.PostgisEntryR\apostgis\x12M\n" +
 	"\rbinlog_server\x18\x1c \x03(\v2(.version.VersionMatrix.BinlogServerEntryR\fbinlogServer\x12C\n" +
-	"\tpgupgrade\x18\x1d \x03(\v2%.version.VersionMatrix.PgupgradeEntryR\tpgupgrade\x1aK\n" +
+	"\tpgupgrade\x18\x1d \x03(\v2%.version.VersionMatrix.PgupgradeEntryR\tpgupgrade\x12F\n" +
+	"\n" +
+	"components\x18\x1e \x03(\v2&.version.VersionMatrix.ComponentsEntryR\n" +
+	"components\x1aK\n" +
 	"\vMongodEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12&\n" +
 	"\x05value\x18\x02 \x01(\v2\x10.version.VersionR\x05value:\x028\x01\x1aH\n" +
@@ -2151,33 +3101,62 @@ const file_api_version_proto_rawDesc = "" +
 	"\x05value\x18\x02 \x01(\v2\x10.version.VersionR\x05value:\x028\x01\x1aN\n" +
 	"\x0ePgupgradeEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12&\n" +
-	"\x05value\x18\x02 \x01(\v2\x10.version.VersionR\x05value:\x028\x01\"w\n" +
+	"\x05value\x18\x02 \x01(\v2\x10.version.VersionR\x05value:\x028\x01\x1aY\n" +
+	"\x0fComponentsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
+	"\x05value\x18\x02 \x01(\v2\x1a.version.ComponentVersionsR\x05value:\x028\x01\"\xa8\x01\n" +
+	"\x11ComponentVersions\x12D\n" +
+	"\bversions\x18\x01 \x03(\v2(.version.ComponentVersions.VersionsEntryR\bversions\x1aM\n" +
+	"\rVersionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12&\n" +
+	"\x05value\x18\x02 \x01(\v2\x10.version.VersionR\x05value:\x028\x01\"\x9b\x02\n" +
 	"\x0fOperatorVersion\x12\x18\n" +
 	"\aproduct\x18\x01 \x01(\tR\aproduct\x12\x1a\n" +
 	"\boperator\x18\x02 \x01(\tR\boperator\x12.\n" +
-	"\x06matrix\x18\x03 \x01(\v2\x16.version.VersionMatrixR\x06matrix\"G\n" +
+	"\x06matrix\x18\x03 \x01(\v2\x16.version.VersionMatrixR\x06matrix\x126\n" +
+	"\x17supported_kube_versions\x18\x04 \x01(\tR\x15supportedKubeVersions\x12/\n" +
+	"\x13supported_platforms\x18\x05 \x03(\tR\x12supportedPlatforms\x129\n" +
+	"\n" +
+	"publish_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tpublishAt\"\xa4\x01\n" +
 	"\x0fVersionResponse\x124\n" +
-	"\bversions\x18\x01 \x03(\v2\x18.version.OperatorVersionR\bversions\"H\n" +
+	"\bversions\x18\x01 \x03(\v2\x18.version.OperatorVersionR\bversions\x12?\n" +
+	"\vexplanation\x18\x02 \x03(\v2\x1d.version.ComponentExplanationR\vexplanation\x12\x1a\n" +
+	"\bwarnings\x18\x03 \x03(\tR\bwarnings\"\x8f\x01\n" +
+	"\x14ComponentExplanation\x12\x1c\n" +
+	"\tcomponent\x18\x01 \x01(\tR\tcomponent\x12=\n" +
+	"\n" +
+	"candidates\x18\x02 \x03(\v2\x1d.version.CandidateExplanationR\n" +
+	"candidates\x12\x1a\n" +
+	"\bselected\x18\x03 \x03(\tR\bselected\"d\n" +
+	"\x14CandidateExplanation\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12\x1a\n" +
+	"\bselected\x18\x02 \x01(\bR\bselected\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"H\n" +
 	"\x10OperatorResponse\x124\n" +
-	"\bversions\x18\x01 \x03(\v2\x18.version.OperatorVersionR\bversions\"G\n" +
+	"\bversions\x18\x01 \x03(\v2\x18.version.OperatorVersionR\bversions\"o\n" +
 	"\x0fProductResponse\x124\n" +
-	"\bversions\x18\x01 \x03(\v2\x18.version.OperatorVersionR\bversions\"\xbd\x02\n" +
+	"\bversions\x18\x01 \x03(\v2\x18.version.OperatorVersionR\bversions\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xf8\x02\n" +
 	"\x0fMetadataVersion\x12\x18\n" +
 	"\aversion\x18\x01 \x01(\tR\aversion\x12K\n" +
 	"\vrecommended\x18\x02 \x03(\v2).version.MetadataVersion.RecommendedEntryR\vrecommended\x12E\n" +
-	"\tsupported\x18\x03 \x03(\v2'.version.MetadataVersion.SupportedEntryR\tsupported\x1a>\n" +
+	"\tsupported\x18\x03 \x03(\v2'.version.MetadataVersion.SupportedEntryR\tsupported\x129\n" +
+	"\n" +
+	"publish_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tpublishAt\x1a>\n" +
 	"\x10RecommendedEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a<\n" +
 	"\x0eSupportedEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xf6\x02\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xb1\x03\n" +
 	"\x11MetadataV2Version\x12\x18\n" +
 	"\aversion\x18\x01 \x01(\tR\aversion\x12M\n" +
 	"\vrecommended\x18\x02 \x03(\v2+.version.MetadataV2Version.RecommendedEntryR\vrecommended\x12G\n" +
 	"\tsupported\x18\x03 \x03(\v2).version.MetadataV2Version.SupportedEntryR\tsupported\x121\n" +
 	"\n" +
-	"image_info\x18\x04 \x01(\v2\x12.version.VersionV2R\timageInfo\x1a>\n" +
+	"image_info\x18\x04 \x01(\v2\x12.version.VersionV2R\timageInfo\x129\n" +
+	"\n" +
+	"publish_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tpublishAt\x1a>\n" +
 	"\x10RecommendedEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a<\n" +
@@ -2200,14 +3179,16 @@ const file_api_version_proto_rawDesc = "" +
 	"\vrecommended\x10\x01\x12\r\n" +
 	"\tavailable\x10\x02\x12\f\n" +
 	"\brequired\x10\x03\x12\f\n" +
-	"\bdisabled\x10\x042\xa9\t\n" +
+	"\bdisabled\x10\x042\xec\n" +
+	"\n" +
 	"\x0eVersionService\x12\xa9\x01\n" +
 	"\x05Apply\x12\x15.version.ApplyRequest\x1a\x18.version.VersionResponse\"o\x92A3\x12\x10Specific version\x1a\x1fReturn specific product version\x82\xd3\xe4\x93\x023\x121/versions/v1/{product}/{operator_version}/{apply}\x12\xd5\x01\n" +
 	"\bOperator\x12\x18.version.OperatorRequest\x1a\x19.version.OperatorResponse\"\x93\x01\x92A_\x12.Product versions for specific operator version\x1a-Return product versions for specific operator\x82\xd3\xe4\x93\x02+\x12)/versions/v1/{product}/{operator_version}\x12\xb4\x01\n" +
 	"\aProduct\x12\x17.version.ProductRequest\x1a\x18.version.ProductResponse\"v\x92AU\x12)Product versions for all operator version\x1a(Return product versions for all operator\x82\xd3\xe4\x93\x02\x18\x12\x16/versions/v1/{product}\x12\xa5\x01\n" +
 	"\bMetadata\x12\x18.version.MetadataRequest\x1a\x19.version.MetadataResponse\"d\x92AC\x12\x16Metadata for a product\x1a)Return metadata information for a product\x82\xd3\xe4\x93\x02\x18\x12\x16/metadata/v1/{product}\x12\xcf\x01\n" +
 	"\n" +
-	"MetadataV2\x12\x18.version.MetadataRequest\x1a\x1b.version.MetadataV2Response\"\x89\x01\x92Ah\x12\x19v2 metadata for a product\x1aKReturn metadata information with additional image information for a product\x82\xd3\xe4\x93\x02\x18\x12\x16/metadata/v2/{product}\x12\xe1\x01\n" +
+	"MetadataV2\x12\x18.version.MetadataRequest\x1a\x1b.version.MetadataV2Response\"\x89\x01\x92Ah\x12\x19v2 metadata for a product\x1aKReturn metadata information with additional image information for a product\x82\xd3\xe4\x93\x02\x18\x12\x16/metadata/v2/{product}\x12\xc0\x01\n" +
+	"\vPlanUpgrade\x12\x1b.version.PlanUpgradeRequest\x1a\x1c.version.PlanUpgradeResponse\"v\x92AQ\x12\fUpgrade plan\x1aAReturn ordered upgrade steps with image versions for each of them\x82\xd3\xe4\x93\x02\x1c\x12\x1a/upgrade-plan/v1/{product}\x12\xe1\x01\n" +
 	"\x0fGetReleaseNotes\x12\x1f.version.GetReleaseNotesRequest\x1a .version.GetReleaseNotesResponse\"\x8a\x01\x92AZ\x12,Gets the release notes for a product version\x1a*Return release notes for a product version\x82\xd3\xe4\x93\x02'\x12%/release-notes/v1/{product}/{version}B\xaa\x03\x92A\x97\x02\x12\x052\x031.0*\x02\x01\x02r\x89\x02\n" +
 	"\xce\x01This service provides version information and image paths for supporting the use of Percona Kubernetes Operators in a variety of scenarios while enabling specific version deployments and automated upgrades.\x126https://github.com/Percona-Lab/percona-version-service\n" +
 	"\vcom.versionB\fVersionProtoP\x01Z6github.com/Percona-Lab/percona-version-service/version\xa2\x02\x03VXX\xaa\x02\aVersion\xca\x02\aVersion\xe2\x02\x13Version\\GPBMetadata\xea\x02\aVersionb\x06proto3"
@@ -2225,151 +3206,187 @@ func file_api_version_proto_rawDescGZIP() []byte {
 }
 
 var file_api_version_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_api_version_proto_msgTypes = make([]protoimpl.MessageInfo, 50)
+var file_api_version_proto_msgTypes = make([]protoimpl.MessageInfo, 64)
 var file_api_version_proto_goTypes = []any{
 	(Status)(0),                     // 0: version.Status
 	(*ApplyRequest)(nil),            // 1: version.ApplyRequest
 	(*OperatorRequest)(nil),         // 2: version.OperatorRequest
 	(*ProductRequest)(nil),          // 3: version.ProductRequest
 	(*MetadataRequest)(nil),         // 4: version.MetadataRequest
-	(*Version)(nil),                 // 5: version.Version
-	(*VersionV2)(nil),               // 6: version.VersionV2
-	(*VersionMatrix)(nil),           // 7: version.VersionMatrix
-	(*OperatorVersion)(nil),         // 8: version.OperatorVersion
-	(*VersionResponse)(nil),         // 9: version.VersionResponse
-	(*OperatorResponse)(nil),        // 10: version.OperatorResponse
-	(*ProductResponse)(nil),         // 11: version.ProductResponse
-	(*MetadataVersion)(nil),         // 12: version.MetadataVersion
-	(*MetadataV2Version)(nil),       // 13: version.MetadataV2Version
-	(*MetadataResponse)(nil),        // 14: version.MetadataResponse
-	(*MetadataV2Response)(nil),      // 15: version.MetadataV2Response
-	(*GetReleaseNotesRequest)(nil),  // 16: version.GetReleaseNotesRequest
-	(*GetReleaseNotesResponse)(nil), // 17: version.GetReleaseNotesResponse
-	nil,                             // 18: version.VersionMatrix.MongodEntry
-	nil,                             // 19: version.VersionMatrix.PxcEntry
-	nil,                             // 20: version.VersionMatrix.PmmEntry
-	nil,                             // 21: version.VersionMatrix.ProxysqlEntry
-	nil,                             // 22: version.VersionMatrix.HaproxyEntry
-	nil,                             // 23: version.VersionMatrix.BackupEntry
-	nil,                             // 24: version.VersionMatrix.OperatorEntry
-	nil,                             // 25: version.VersionMatrix.LogCollectorEntry
-	nil,                             // 26: version.VersionMatrix.PostgresqlEntry
-	nil,                             // 27: version.VersionMatrix.PgbackrestEntry
-	nil,                             // 28: version.VersionMatrix.PgbackrestRepoEntry
-	nil,                             // 29: version.VersionMatrix.PgbadgerEntry
-	nil,                             // 30: version.VersionMatrix.PgbouncerEntry
-	nil,                             // 31: version.VersionMatrix.PxcOperatorEntry
-	nil,                             // 32: version.VersionMatrix.PsmdbOperatorEntry
-	nil,                             // 33: version.VersionMatrix.PgOperatorApiserverEntry
-	nil,                             // 34: version.VersionMatrix.PgOperatorEventEntry
-	nil,                             // 35: version.VersionMatrix.PgOperatorRmdataEntry
-	nil,                             // 36: version.VersionMatrix.PgOperatorSchedulerEntry
-	nil,                             // 37: version.VersionMatrix.PgOperatorEntry
-	nil,                             // 38: version.VersionMatrix.PgOperatorDeployerEntry
-	nil,                             // 39: version.VersionMatrix.PsOperatorEntry
-	nil,                             // 40: version.VersionMatrix.MysqlEntry
-	nil,                             // 41: version.VersionMatrix.RouterEntry
-	nil,                             // 42: version.VersionMatrix.OrchestratorEntry
-	nil,                             // 43: version.VersionMatrix.ToolkitEntry
-	nil,                             // 44: version.VersionMatrix.PostgisEntry
-	nil,                             // 45: version.VersionMatrix.BinlogServerEntry
-	nil,                             // 46: version.VersionMatrix.PgupgradeEntry
-	nil,                             // 47: version.MetadataVersion.RecommendedEntry
-	nil,                             // 48: version.MetadataVersion.SupportedEntry
-	nil,                             // 49: version.MetadataV2Version.RecommendedEntry
-	nil,                             // 50: version.MetadataV2Version.SupportedEntry
-	(*timestamppb.Timestamp)(nil),   // 51: google.protobuf.Timestamp
+	(*PlanUpgradeRequest)(nil),      // 5: version.PlanUpgradeRequest
+	(*UpgradeHop)(nil),              // 6: version.UpgradeHop
+	(*PlanUpgradeResponse)(nil),     // 7: version.PlanUpgradeResponse
+	(*Version)(nil),                 // 8: version.Version
+	(*Withdrawal)(nil),              // 9: version.Withdrawal
+	(*WithdrawnVersion)(nil),        // 10: version.WithdrawnVersion
+	(*Rollout)(nil),                 // 11: version.Rollout
+	(*RolloutStage)(nil),            // 12: version.RolloutStage
+	(*DistributionImage)(nil),       // 13: version.DistributionImage
+	(*VersionV2)(nil),               // 14: version.VersionV2
+	(*VersionMatrix)(nil),           // 15: version.VersionMatrix
+	(*ComponentVersions)(nil),       // 16: version.ComponentVersions
+	(*OperatorVersion)(nil),         // 17: version.OperatorVersion
+	(*VersionResponse)(nil),         // 18: version.VersionResponse
+	(*ComponentExplanation)(nil),    // 19: version.ComponentExplanation
+	(*CandidateExplanation)(nil),    // 20: version.CandidateExplanation
+	(*OperatorResponse)(nil),        // 21: version.OperatorResponse
+	(*ProductResponse)(nil),         // 22: version.ProductResponse
+	(*MetadataVersion)(nil),         // 23: version.MetadataVersion
+	(*MetadataV2Version)(nil),       // 24: version.MetadataV2Version
+	(*MetadataResponse)(nil),        // 25: version.MetadataResponse
+	(*MetadataV2Response)(nil),      // 26: version.MetadataV2Response
+	(*GetReleaseNotesRequest)(nil),  // 27: version.GetReleaseNotesRequest
+	(*GetReleaseNotesResponse)(nil), // 28: version.GetReleaseNotesResponse
+	nil,                             // 29: version.Version.DistributionsEntry
+	nil,                             // 30: version.VersionMatrix.MongodEntry
+	nil,                             // 31: version.VersionMatrix.PxcEntry
+	nil,                             // 32: version.VersionMatrix.PmmEntry
+	nil,                             // 33: version.VersionMatrix.ProxysqlEntry
+	nil,                             // 34: version.VersionMatrix.HaproxyEntry
+	nil,                             // 35: version.VersionMatrix.BackupEntry
+	nil,                             // 36: version.VersionMatrix.OperatorEntry
+	nil,                             // 37: version.VersionMatrix.LogCollectorEntry
+	nil,                             // 38: version.VersionMatrix.PostgresqlEntry
+	nil,                             // 39: version.VersionMatrix.PgbackrestEntry
+	nil,                             // 40: version.VersionMatrix.PgbackrestRepoEntry
+	nil,                             // 41: version.VersionMatrix.PgbadgerEntry
+	nil,                             // 42: version.VersionMatrix.PgbouncerEntry
+	nil,                             // 43: version.VersionMatrix.PxcOperatorEntry
+	nil,                             // 44: version.VersionMatrix.PsmdbOperatorEntry
+	nil,                             // 45: version.VersionMatrix.PgOperatorApiserverEntry
+	nil,                             // 46: version.VersionMatrix.PgOperatorEventEntry
+	nil,                             // 47: version.VersionMatrix.PgOperatorRmdataEntry
+	nil,                             // 48: version.VersionMatrix.PgOperatorSchedulerEntry
+	nil,                             // 49: version.VersionMatrix.PgOperatorEntry
+	nil,                             // 50: version.VersionMatrix.PgOperatorDeployerEntry
+	nil,                             // 51: version.VersionMatrix.PsOperatorEntry
+	nil,                             // 52: version.VersionMatrix.MysqlEntry
+	nil,                             // 53: version.VersionMatrix.RouterEntry
+	nil,                             // 54: version.VersionMatrix.OrchestratorEntry
+	nil,                             // 55: version.VersionMatrix.ToolkitEntry
+	nil,                             // 56: version.VersionMatrix.PostgisEntry
+	nil,                             // 57: version.VersionMatrix.BinlogServerEntry
+	nil,                             // 58: version.VersionMatrix.PgupgradeEntry
+	nil,                             // 59: version.VersionMatrix.ComponentsEntry
+	nil,                             // 60: version.ComponentVersions.VersionsEntry
+	nil,                             // 61: version.MetadataVersion.RecommendedEntry
+	nil,                             // 62: version.MetadataVersion.SupportedEntry
+	nil,                             // 63: version.MetadataV2Version.RecommendedEntry
+	nil,                             // 64: version.MetadataV2Version.SupportedEntry
+	(*timestamppb.Timestamp)(nil),   // 65: google.protobuf.Timestamp
 }
 var file_api_version_proto_depIdxs = []int32{
-	0,  // 0: version.Version.status:type_name -> version.Status
-	51, // 1: version.VersionV2.image_release_timestamp:type_name -> google.protobuf.Timestamp
-	0,  // 2: version.VersionV2.status:type_name -> version.Status
-	18, // 3: version.VersionMatrix.mongod:type_name -> version.VersionMatrix.MongodEntry
-	19, // 4: version.VersionMatrix.pxc:type_name -> version.VersionMatrix.PxcEntry
-	20, // 5: version.VersionMatrix.pmm:type_name -> version.VersionMatrix.PmmEntry
-	21, // 6: version.VersionMatrix.proxysql:type_name -> version.VersionMatrix.ProxysqlEntry
-	22, // 7: version.VersionMatrix.haproxy:type_name -> version.VersionMatrix.HaproxyEntry
-	23, // 8: version.VersionMatrix.backup:type_name -> version.VersionMatrix.BackupEntry
-	24, // 9: version.VersionMatrix.operator:type_name -> version.VersionMatrix.OperatorEntry
-	25, // 10: version.VersionMatrix.log_collector:type_name -> version.VersionMatrix.LogCollectorEntry
-	26, // 11: version.VersionMatrix.postgresql:type_name -> version.VersionMatrix.PostgresqlEntry
-	27, // 12: version.VersionMatrix.pgbackrest:type_name -> version.VersionMatrix.PgbackrestEntry
-	28, // 13: version.VersionMatrix.pgbackrest_repo:type_name -> version.VersionMatrix.PgbackrestRepoEntry
-	29, // 14: version.VersionMatrix.pgbadger:type_name -> version.VersionMatrix.PgbadgerEntry
-	30, // 15: version.VersionMatrix.pgbouncer:type_name -> version.VersionMatrix.PgbouncerEntry
-	31, // 16: version.VersionMatrix.pxc_operator:type_name -> version.VersionMatrix.PxcOperatorEntry
-	32, // 17: version.VersionMatrix.psmdb_operator:type_name -> version.VersionMatrix.PsmdbOperatorEntry
-	33, // 18: version.VersionMatrix.pg_operator_apiserver:type_name -> version.VersionMatrix.PgOperatorApiserverEntry
-	34, // 19: version.VersionMatrix.pg_operator_event:type_name -> version.VersionMatrix.PgOperatorEventEntry
-	35, // 20: version.VersionMatrix.pg_operator_rmdata:type_name -> version.VersionMatrix.PgOperatorRmdataEntry
-	36, // 21: version.VersionMatrix.pg_operator_scheduler:type_name -> version.VersionMatrix.PgOperatorSchedulerEntry
-	37, // 22: version.VersionMatrix.pg_operator:type_name -> version.VersionMatrix.PgOperatorEntry
-	38, // 23: version.VersionMatrix.pg_operator_deployer:type_name -> version.VersionMatrix.PgOperatorDeployerEntry
-	39, // 24: version.VersionMatrix.ps_operator:type_name -> version.VersionMatrix.PsOperatorEntry
-	40, // 25: version.VersionMatrix.mysql:type_name -> version.VersionMatrix.MysqlEntry
-	41, // 26: version.VersionMatrix.router:type_name -> version.VersionMatrix.RouterEntry
-	42, // 27: version.VersionMatrix.orchestrator:type_name -> version.VersionMatrix.OrchestratorEntry
-	43, // 28: version.VersionMatrix.toolkit:type_name -> version.VersionMatrix.ToolkitEntry
-	44, // 29: version.VersionMatrix.postgis:type_name -> version.VersionMatrix.PostgisEntry
-	45, // 30: version.VersionMatrix.binlog_server:type_name -> version.VersionMatrix.BinlogServerEntry
-	46, // 31: version.VersionMatrix.pgupgrade:type_name -> version.VersionMatrix.PgupgradeEntry
-	7,  // 32: version.OperatorVersion.matrix:type_name -> version.VersionMatrix
-	8,  // 33: version.VersionResponse.versions:type_name -> version.OperatorVersion
-	8,  // 34: version.OperatorResponse.versions:type_name -> version.OperatorVersion
-	8,  // 35: version.ProductResponse.versions:type_name -> version.OperatorVersion
-	47, // 36: version.MetadataVersion.recommended:type_name -> version.MetadataVersion.RecommendedEntry
-	48, // 37: version.MetadataVersion.supported:type_name -> version.MetadataVersion.SupportedEntry
-	49, // 38: version.MetadataV2Version.recommended:type_name -> version.MetadataV2Version.RecommendedEntry
-	50, // 39: version.MetadataV2Version.supported:type_name -> version.MetadataV2Version.SupportedEntry
-	6,  // 40: version.MetadataV2Version.image_info:type_name -> version.VersionV2
-	12, // 41: version.MetadataResponse.versions:type_name -> version.MetadataVersion
-	13, // 42: version.MetadataV2Response.versions:type_name -> version.MetadataV2Version
-	5,  // 43: version.VersionMatrix.MongodEntry.value:type_name -> version.Version
-	5,  // 44: version.VersionMatrix.PxcEntry.value:type_name -> version.Version
-	5,  // 45: version.VersionMatrix.PmmEntry.value:type_name -> version.Version
-	5,  // 46: version.VersionMatrix.ProxysqlEntry.value:type_name -> version.Version
-	5,  // 47: version.VersionMatrix.HaproxyEntry.value:type_name -> version.Version
-	5,  // 48: version.VersionMatrix.BackupEntry.value:type_name -> version.Version
-	5,  // 49: version.VersionMatrix.OperatorEntry.value:type_name -> version.Version
-	5,  // 50: version.VersionMatrix.LogCollectorEntry.value:type_name -> version.Version
-	5,  // 51: version.VersionMatrix.PostgresqlEntry.value:type_name -> version.Version
-	5,  // 52: version.VersionMatrix.PgbackrestEntry.value:type_name -> version.Version
-	5,  // 53: version.VersionMatrix.PgbackrestRepoEntry.value:type_name -> version.Version
-	5,  // 54: version.VersionMatrix.PgbadgerEntry.value:type_name -> version.Version
-	5,  // 55: version.VersionMatrix.PgbouncerEntry.value:type_name -> version.Version
-	5,  // 56: version.VersionMatrix.PxcOperatorEntry.value:type_name -> version.Version
-	5,  // 57: version.VersionMatrix.PsmdbOperatorEntry.value:type_name -> version.Version
-	5,  // 58: version.VersionMatrix.PgOperatorApiserverEntry.value:type_name -> version.Version
-	5,  // 59: version.VersionMatrix.PgOperatorEventEntry.value:type_name -> version.Version
-	5,  // 60: version.VersionMatrix.PgOperatorRmdataEntry.value:type_name -> version.Version
-	5,  // 61: version.VersionMatrix.PgOperatorSchedulerEntry.value:type_name -> version.Version
-	5,  // 62: version.VersionMatrix.PgOperatorEntry.value:type_name -> version.Version
-	5,  // 63: version.VersionMatrix.PgOperatorDeployerEntry.value:type_name -> version.Version
-	5,  // 64: version.VersionMatrix.PsOperatorEntry.value:type_name -> version.Version
-	5,  // 65: version.VersionMatrix.MysqlEntry.value:type_name -> version.Version
-	5,  // 66: version.VersionMatrix.RouterEntry.value:type_name -> version.Version
-	5,  // 67: version.VersionMatrix.OrchestratorEntry.value:type_name -> version.Version
-	5,  // 68: version.VersionMatrix.ToolkitEntry.value:type_name -> version.Version
-	5,  // 69: version.VersionMatrix.PostgisEntry.value:type_name -> version.Version
-	5,  // 70: version.VersionMatrix.BinlogServerEntry.value:type_name -> version.Version
-	5,  // 71: version.VersionMatrix.PgupgradeEntry.value:type_name -> version.Version
-	1,  // 72: version.VersionService.Apply:input_type -> version.ApplyRequest
-	2,  // 73: version.VersionService.Operator:input_type -> version.OperatorRequest
-	3,  // 74: version.VersionService.Product:input_type -> version.ProductRequest
-	4,  // 75: version.VersionService.Metadata:input_type -> version.MetadataRequest
-	4,  // 76: version.VersionService.MetadataV2:input_type -> version.MetadataRequest
-	16, // 77: version.VersionService.GetReleaseNotes:input_type -> version.GetReleaseNotesRequest
-	9,  // 78: version.VersionService.Apply:output_type -> version.VersionResponse
-	10, // 79: version.VersionService.Operator:output_type -> version.OperatorResponse
-	11, // 80: version.VersionService.Product:output_type -> version.ProductResponse
-	14, // 81: version.VersionService.Metadata:output_type -> version.MetadataResponse
-	15, // 82: version.VersionService.MetadataV2:output_type -> version.MetadataV2Response
-	17, // 83: version.VersionService.GetReleaseNotes:output_type -> version.GetReleaseNotesResponse
-	78, // [78:84] is the sub-list for method output_type
-	72, // [72:78] is the sub-list for method input_type
-	72, // [72:72] is the sub-list for extension type_name
-	72, // [72:72] is the sub-list for extension extendee
-	0,  // [0:72] is the sub-list for field type_name
+	15, // 0: version.UpgradeHop.matrix:type_name -> version.VersionMatrix
+	6,  // 1: version.PlanUpgradeResponse.hops:type_name -> version.UpgradeHop
+	0,  // 2: version.Version.status:type_name -> version.Status
+	29, // 3: version.Version.distributions:type_name -> version.Version.DistributionsEntry
+	11, // 4: version.Version.rollout:type_name -> version.Rollout
+	9,  // 5: version.Version.withdrawn:type_name -> version.Withdrawal
+	65, // 6: version.Version.publish_at:type_name -> google.protobuf.Timestamp
+	9,  // 7: version.WithdrawnVersion.withdrawal:type_name -> version.Withdrawal
+	12, // 8: version.Rollout.stages:type_name -> version.RolloutStage
+	65, // 9: version.Rollout.paused_at:type_name -> google.protobuf.Timestamp
+	65, // 10: version.RolloutStage.start:type_name -> google.protobuf.Timestamp
+	65, // 11: version.VersionV2.image_release_timestamp:type_name -> google.protobuf.Timestamp
+	0,  // 12: version.VersionV2.status:type_name -> version.Status
+	30, // 13: version.VersionMatrix.mongod:type_name -> version.VersionMatrix.MongodEntry
+	31, // 14: version.VersionMatrix.pxc:type_name -> version.VersionMatrix.PxcEntry
+	32, // 15: version.VersionMatrix.pmm:type_name -> version.VersionMatrix.PmmEntry
+	33, // 16: version.VersionMatrix.proxysql:type_name -> version.VersionMatrix.ProxysqlEntry
+	34, // 17: version.VersionMatrix.haproxy:type_name -> version.VersionMatrix.HaproxyEntry
+	35, // 18: version.VersionMatrix.backup:type_name -> version.VersionMatrix.BackupEntry
+	36, // 19: version.VersionMatrix.operator:type_name -> version.VersionMatrix.OperatorEntry
+	37, // 20: version.VersionMatrix.log_collector:type_name -> version.VersionMatrix.LogCollectorEntry
+	38, // 21: version.VersionMatrix.postgresql:type_name -> version.VersionMatrix.PostgresqlEntry
+	39, // 22: version.VersionMatrix.pgbackrest:type_name -> version.VersionMatrix.PgbackrestEntry
+	40, // 23: version.VersionMatrix.pgbackrest_repo:type_name -> version.VersionMatrix.PgbackrestRepoEntry
+	41, // 24: version.VersionMatrix.pgbadger:type_name -> version.VersionMatrix.PgbadgerEntry
+	42, // 25: version.VersionMatrix.pgbouncer:type_name -> version.VersionMatrix.PgbouncerEntry
+	43, // 26: version.VersionMatrix.pxc_operator:type_name -> version.VersionMatrix.PxcOperatorEntry
+	44, // 27: version.VersionMatrix.psmdb_operator:type_name -> version.VersionMatrix.PsmdbOperatorEntry
+	45, // 28: version.VersionMatrix.pg_operator_apiserver:type_name -> version.VersionMatrix.PgOperatorApiserverEntry
+	46, // 29: version.VersionMatrix.pg_operator_event:type_name -> version.VersionMatrix.PgOperatorEventEntry
+	47, // 30: version.VersionMatrix.pg_operator_rmdata:type_name -> version.VersionMatrix.PgOperatorRmdataEntry
+	48, // 31: version.VersionMatrix.pg_operator_scheduler:type_name -> version.VersionMatrix.PgOperatorSchedulerEntry
+	49, // 32: version.VersionMatrix.pg_operator:type_name -> version.VersionMatrix.PgOperatorEntry
+	50, // 33: version.VersionMatrix.pg_operator_deployer:type_name -> version.VersionMatrix.PgOperatorDeployerEntry
+	51, // 34: version.VersionMatrix.ps_operator:type_name -> version.VersionMatrix.PsOperatorEntry
+	52, // 35: version.VersionMatrix.mysql:type_name -> version.VersionMatrix.MysqlEntry
+	53, // 36: version.VersionMatrix.router:type_name -> version.VersionMatrix.RouterEntry
+	54, // 37: version.VersionMatrix.orchestrator:type_name -> version.VersionMatrix.OrchestratorEntry
+	55, // 38: version.VersionMatrix.toolkit:type_name -> version.VersionMatrix.ToolkitEntry
+	56, // 39: version.VersionMatrix.postgis:type_name -> version.VersionMatrix.PostgisEntry
+	57, // 40: version.VersionMatrix.binlog_server:type_name -> version.VersionMatrix.BinlogServerEntry
+	58, // 41: version.VersionMatrix.pgupgrade:type_name -> version.VersionMatrix.PgupgradeEntry
+	59, // 42: version.VersionMatrix.components:type_name -> version.VersionMatrix.ComponentsEntry
+	60, // 43: version.ComponentVersions.versions:type_name -> version.ComponentVersions.VersionsEntry
+	15, // 44: version.OperatorVersion.matrix:type_name -> version.VersionMatrix
+	65, // 45: version.OperatorVersion.publish_at:type_name -> google.protobuf.Timestamp
+	17, // 46: version.VersionResponse.versions:type_name -> version.OperatorVersion
+	19, // 47: version.VersionResponse.explanation:type_name -> version.ComponentExplanation
+	20, // 48: version.ComponentExplanation.candidates:type_name -> version.CandidateExplanation
+	17, // 49: version.OperatorResponse.versions:type_name -> version.OperatorVersion
+	17, // 50: version.ProductResponse.versions:type_name -> version.OperatorVersion
+	61, // 51: version.MetadataVersion.recommended:type_name -> version.MetadataVersion.RecommendedEntry
+	62, // 52: version.MetadataVersion.supported:type_name -> version.MetadataVersion.SupportedEntry
+	65, // 53: version.MetadataVersion.publish_at:type_name -> google.protobuf.Timestamp
+	63, // 54: version.MetadataV2Version.recommended:type_name -> version.MetadataV2Version.RecommendedEntry
+	64, // 55: version.MetadataV2Version.supported:type_name -> version.MetadataV2Version.SupportedEntry
+	14, // 56: version.MetadataV2Version.image_info:type_name -> version.VersionV2
+	65, // 57: version.MetadataV2Version.publish_at:type_name -> google.protobuf.Timestamp
+	23, // 58: version.MetadataResponse.versions:type_name -> version.MetadataVersion
+	24, // 59: version.MetadataV2Response.versions:type_name -> version.MetadataV2Version
+	13, // 60: version.Version.DistributionsEntry.value:type_name -> version.DistributionImage
+	8,  // 61: version.VersionMatrix.MongodEntry.value:type_name -> version.Version
+	8,  // 62: version.VersionMatrix.PxcEntry.value:type_name -> version.Version
+	8,  // 63: version.VersionMatrix.PmmEntry.value:type_name -> version.Version
+	8,  // 64: version.VersionMatrix.ProxysqlEntry.value:type_name -> version.Version
+	8,  // 65: version.VersionMatrix.HaproxyEntry.value:type_name -> version.Version
+	8,  // 66: version.VersionMatrix.BackupEntry.value:type_name -> version.Version
+	8,  // 67: version.VersionMatrix.OperatorEntry.value:type_name -> version.Version
+	8,  // 68: version.VersionMatrix.LogCollectorEntry.value:type_name -> version.Version
+	8,  // 69: version.VersionMatrix.PostgresqlEntry.value:type_name -> version.Version
+	8,  // 70: version.VersionMatrix.PgbackrestEntry.value:type_name -> version.Version
+	8,  // 71: version.VersionMatrix.PgbackrestRepoEntry.value:type_name -> version.Version
+	8,  // 72: version.VersionMatrix.PgbadgerEntry.value:type_name -> version.Version
+	8,  // 73: version.VersionMatrix.PgbouncerEntry.value:type_name -> version.Version
+	8,  // 74: version.VersionMatrix.PxcOperatorEntry.value:type_name -> version.Version
+	8,  // 75: version.VersionMatrix.PsmdbOperatorEntry.value:type_name -> version.Version
+	8,  // 76: version.VersionMatrix.PgOperatorApiserverEntry.value:type_name -> version.Version
+	8,  // 77: version.VersionMatrix.PgOperatorEventEntry.value:type_name -> version.Version
+	8,  // 78: version.VersionMatrix.PgOperatorRmdataEntry.value:type_name -> version.Version
+	8,  // 79: version.VersionMatrix.PgOperatorSchedulerEntry.value:type_name -> version.Version
+	8,  // 80: version.VersionMatrix.PgOperatorEntry.value:type_name -> version.Version
+	8,  // 81: version.VersionMatrix.PgOperatorDeployerEntry.value:type_name -> version.Version
+	8,  // 82: version.VersionMatrix.PsOperatorEntry.value:type_name -> version.Version
+	8,  // 83: version.VersionMatrix.MysqlEntry.value:type_name -> version.Version
+	8,  // 84: version.VersionMatrix.RouterEntry.value:type_name -> version.Version
+	8,  // 85: version.VersionMatrix.OrchestratorEntry.value:type_name -> version.Version
+	8,  // 86: version.VersionMatrix.ToolkitEntry.value:type_name -> version.Version
+	8,  // 87: version.VersionMatrix.PostgisEntry.value:type_name -> version.Version
+	8,  // 88: version.VersionMatrix.BinlogServerEntry.value:type_name -> version.Version
+	8,  // 89: version.VersionMatrix.PgupgradeEntry.value:type_name -> version.Version
+	16, // 90: version.VersionMatrix.ComponentsEntry.value:type_name -> version.ComponentVersions
+	8,  // 91: version.ComponentVersions.VersionsEntry.value:type_name -> version.Version
+	1,  // 92: version.VersionService.Apply:input_type -> version.ApplyRequest
+	2,  // 93: version.VersionService.Operator:input_type -> version.OperatorRequest
+	3,  // 94: version.VersionService.Product:input_type -> version.ProductRequest
+	4,  // 95: version.VersionService.Metadata:input_type -> version.MetadataRequest
+	4,  // 96: version.VersionService.MetadataV2:input_type -> version.MetadataRequest
+	5,  // 97: version.VersionService.PlanUpgrade:input_type -> version.PlanUpgradeRequest
+	27, // 98: version.VersionService.GetReleaseNotes:input_type -> version.GetReleaseNotesRequest
+	18, // 99: version.VersionService.Apply:output_type -> version.VersionResponse
+	21, // 100: version.VersionService.Operator:output_type -> version.OperatorResponse
+	22, // 101: version.VersionService.Product:output_type -> version.ProductResponse
+	25, // 102: version.VersionService.Metadata:output_type -> version.MetadataResponse
+	26, // 103: version.VersionService.MetadataV2:output_type -> version.MetadataV2Response
+	7,  // 104: version.VersionService.PlanUpgrade:output_type -> version.PlanUpgradeResponse
+	28, // 105: version.VersionService.GetReleaseNotes:output_type -> version.GetReleaseNotesResponse
+	99, // [99:106] is the sub-list for method output_type
+	92, // [92:99] is the sub-list for method input_type
+	92, // [92:92] is the sub-list for extension type_name
+	92, // [92:92] is the sub-list for extension extendee
+	0,  // [0:92] is the sub-list for field type_name
 }
 
 func init() { file_api_version_proto_init() }
@@ -2383,7 +3400,7 @@ func file_api_version_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_version_proto_rawDesc), len(file_api_version_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   50,
+			NumMessages:   64,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
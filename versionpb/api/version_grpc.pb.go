@@ -24,6 +24,7 @@ const (
 	VersionService_Product_FullMethodName         = "/version.VersionService/Product"
 	VersionService_Metadata_FullMethodName        = "/version.VersionService/Metadata"
 	VersionService_MetadataV2_FullMethodName      = "/version.VersionService/MetadataV2"
+	VersionService_PlanUpgrade_FullMethodName     = "/version.VersionService/PlanUpgrade"
 	VersionService_GetReleaseNotes_FullMethodName = "/version.VersionService/GetReleaseNotes"
 )
 
@@ -43,6 +44,8 @@ type VersionServiceClient interface {
 	Metadata(ctx context.Context, in *MetadataRequest, opts ...grpc.CallOption) (*MetadataResponse, error)
 	// Metadata v2 provides metadata information about products. It is an extension of Metadata with new fields.
 	MetadataV2(ctx context.Context, in *MetadataRequest, opts ...grpc.CallOption) (*MetadataV2Response, error)
+	// PlanUpgrade provides ordered upgrade steps from the current operator and database versions to the target ones.
+	PlanUpgrade(ctx context.Context, in *PlanUpgradeRequest, opts ...grpc.CallOption) (*PlanUpgradeResponse, error)
 	GetReleaseNotes(ctx context.Context, in *GetReleaseNotesRequest, opts ...grpc.CallOption) (*GetReleaseNotesResponse, error)
 }
 
@@ -104,6 +107,16 @@ func (c *versionServiceClient) MetadataV2(ctx context.Context, in *MetadataReque
 	return out, nil
 }
 
+func (c *versionServiceClient) PlanUpgrade(ctx context.Context, in *PlanUpgradeRequest, opts ...grpc.CallOption) (*PlanUpgradeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PlanUpgradeResponse)
+	err := c.cc.Invoke(ctx, VersionService_PlanUpgrade_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *versionServiceClient) GetReleaseNotes(ctx context.Context, in *GetReleaseNotesRequest, opts ...grpc.CallOption) (*GetReleaseNotesResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetReleaseNotesResponse)
@@ -130,6 +143,8 @@ type VersionServiceServer interface {
 	Metadata(context.Context, *MetadataRequest) (*MetadataResponse, error)
 	// Metadata v2 provides metadata information about products. It is an extension of Metadata with new fields.
 	MetadataV2(context.Context, *MetadataRequest) (*MetadataV2Response, error)
+	// PlanUpgrade provides ordered upgrade steps from the current operator and database versions to the target ones.
+	PlanUpgrade(context.Context, *PlanUpgradeRequest) (*PlanUpgradeResponse, error)
 	GetReleaseNotes(context.Context, *GetReleaseNotesRequest) (*GetReleaseNotesResponse, error)
 	mustEmbedUnimplementedVersionServiceServer()
 }
@@ -153,6 +168,9 @@ func (UnimplementedVersionServiceServer) Metadata(context.Context, *MetadataRequ
 func (UnimplementedVersionServiceServer) MetadataV2(context.Context, *MetadataRequest) (*MetadataV2Response, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method MetadataV2 not implemented")
 }
+func (UnimplementedVersionServiceServer) PlanUpgrade(context.Context, *PlanUpgradeRequest) (*PlanUpgradeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlanUpgrade not implemented")
+}
 func (UnimplementedVersionServiceServer) GetReleaseNotes(context.Context, *GetReleaseNotesRequest) (*GetReleaseNotesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetReleaseNotes not implemented")
 }
@@ -259,6 +277,24 @@ func _VersionService_MetadataV2_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _VersionService_PlanUpgrade_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlanUpgradeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VersionServiceServer).PlanUpgrade(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VersionService_PlanUpgrade_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VersionServiceServer).PlanUpgrade(ctx, req.(*PlanUpgradeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _VersionService_GetReleaseNotes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetReleaseNotesRequest)
 	if err := dec(in); err != nil {
@@ -304,6 +340,10 @@ var VersionService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "MetadataV2",
 			Handler:    _VersionService_MetadataV2_Handler,
 		},
+		{
+			MethodName: "PlanUpgrade",
+			Handler:    _VersionService_PlanUpgrade_Handler,
+		},
 		{
 			MethodName: "GetReleaseNotes",
 			Handler:    _VersionService_GetReleaseNotes_Handler,